@@ -0,0 +1,66 @@
+package cardid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// In text notation a card is its rank (A 2-9 T J Q K) and suit (C D H S), eg "7H" or "TS",
+// or "JK" for a joker. The pack number follows if it is not the first pack, eg "7H1",
+// and a face down card is written in lower case, eg "7h".
+
+const (
+	notationRanks = "A23456789TJQK" // ordinals 1 to 13
+	notationSuits = "CDHS"          // CLUB to SPADE
+	notationJoker = "JK"
+)
+
+// Notation returns the card in text notation
+func (cid CardID) Notation() string {
+	var s string
+	if cid.Joker() || (cid.Suit() == NOSUIT && cid.Ordinal() == 0) {
+		s = notationJoker
+	} else if cid.Ordinal() >= 1 && cid.Ordinal() <= 13 && cid.Suit() >= CLUB && cid.Suit() <= SPADE {
+		s = string(notationRanks[cid.Ordinal()-1]) + string(notationSuits[cid.Suit()-1])
+	} else {
+		s = "??"
+	}
+	if cid.Pack() > 0 {
+		s += strconv.Itoa(cid.Pack())
+	}
+	if cid.Prone() {
+		s = strings.ToLower(s)
+	}
+	return s
+}
+
+// ParseNotation reads a card written in text notation
+func ParseNotation(s string) (CardID, error) {
+	if len(s) < 2 {
+		return 0, fmt.Errorf("'%s' is not a card", s)
+	}
+	var prone bool = s == strings.ToLower(s)
+	var upper string = strings.ToUpper(s)
+	if !prone && s != upper {
+		return 0, fmt.Errorf("'%s' is not a card", s)
+	}
+	var pack int
+	if len(upper) > 2 {
+		var err error
+		if pack, err = strconv.Atoi(upper[2:]); err != nil || pack < 0 || pack > 15 || upper[2] == '+' {
+			return 0, fmt.Errorf("'%s' has a bad pack number", s)
+		}
+	}
+	var cid CardID
+	if upper[:2] == notationJoker {
+		cid = NewCardID(pack, NOSUIT, 0)
+	} else {
+		var rank, suit int = strings.IndexByte(notationRanks, upper[0]), strings.IndexByte(notationSuits, upper[1])
+		if rank == -1 || suit == -1 {
+			return 0, fmt.Errorf("'%s' is not a card", s)
+		}
+		cid = NewCardID(pack, suit+1, rank+1)
+	}
+	return cid.SetProne(prone), nil
+}
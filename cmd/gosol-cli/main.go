@@ -0,0 +1,258 @@
+// gosol-cli deals and plays games without opening a window, to measure how hard the variants are,
+// or to play in a terminal
+//
+//	gosol-cli list
+//	gosol-cli deal -variant Klondike -seed 12345 [-format notation|fc-solve|pysol]
+//	gosol-cli play -variant Klondike -seeds 1-1000 [-player hints|solver] [-maxmoves 1000] [-v]
+//	gosol-cli term -variant Klondike [-seed 12345] [-plain]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"oddstream.games/gosol/core"
+	"oddstream.games/gosol/solver"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: gosol-cli list | deal [flags] | play [flags] | term [flags]")
+	fmt.Fprintln(os.Stderr, "  list  prints the names of the variants")
+	fmt.Fprintln(os.Stderr, "  deal  prints a deal of a variant as text")
+	fmt.Fprintln(os.Stderr, "  play  plays a range of deals of a variant, and reports how many were won")
+	fmt.Fprintln(os.Stderr, "  term  plays a game in the terminal")
+	fmt.Fprintln(os.Stderr, "run gosol-cli deal -h, gosol-cli play -h or gosol-cli term -h for their flags")
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "list":
+		list()
+	case "deal":
+		err = deal(os.Args[2:])
+	case "play":
+		err = play(os.Args[2:])
+	case "term":
+		err = playTerm(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// list prints the names of the variants, in alphabetical order
+func list() {
+	var names []string = append([]string{}, core.VariantGroups["> All"]...)
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Println(name)
+	}
+}
+
+// newBaize makes a Baize of the variant, with the deal of seed
+func newBaize(variant string, seed uint64, options *core.Options) (*core.Baize, error) {
+	b := core.NewBaize(variant, options)
+	if b == nil {
+		return nil, fmt.Errorf("do not know how to play %s; gosol-cli list shows the variants", variant)
+	}
+	b.StartFreshGame(seed)
+	return b, nil
+}
+
+func deal(args []string) error {
+	fs := flag.NewFlagSet("deal", flag.ExitOnError)
+	var variant *string = fs.String("variant", "Klondike", "the variant to deal")
+	var seed *uint64 = fs.Uint64("seed", 0, "the deal number; 0 for a random one")
+	var microsoft *bool = fs.Bool("microsoft", false, "use the Microsoft FreeCell deal with this number")
+	var format *string = fs.String("format", "notation", "notation, "+core.FormatFCSolve+" or "+core.FormatPySol)
+	fs.Parse(args)
+
+	log.SetOutput(io.Discard) // dealing is chatty
+	if *seed == 0 {
+		*seed = core.NewSeed()
+	}
+	b, err := newBaize(*variant, *seed, nil)
+	if err != nil {
+		return err
+	}
+	if *microsoft {
+		if err := b.NewMicrosoftDeal(*seed); err != nil {
+			return err
+		}
+	}
+	var text string
+	if *format == "notation" {
+		text = fmt.Sprintf("# %s deal %d\n", *variant, *seed) + core.FormatPosition(b.Position())
+	} else if text, err = core.ExportPosition(b.Position(), *format); err != nil {
+		return err
+	}
+	fmt.Print(text)
+	return nil
+}
+
+// result is how one deal went
+type result struct {
+	seed     uint64
+	won      bool
+	moves    int
+	percent  int
+	duration time.Duration
+	err      error
+}
+
+func play(args []string) error {
+	fs := flag.NewFlagSet("play", flag.ExitOnError)
+	var variant *string = fs.String("variant", "Klondike", "the variant to play")
+	var seeds *string = fs.String("seeds", "1-100", "the deal numbers to play, eg 1-1000 or 7,9,12")
+	var player *string = fs.String("player", "hints", "hints, to make the best hint each time, or solver, to play the solver's win if it finds one")
+	var maxMoves *int = fs.Int("maxmoves", 1000, "the number of moves the hints player gives up after")
+	var maxNodes *int = fs.Int("maxnodes", 0, "the number of positions the solver gives up after; 0 for its usual limit")
+	var workers *int = fs.Int("workers", runtime.NumCPU(), "the number of deals played at once")
+	var powerMoves *bool = fs.Bool("powermoves", true, "move several cards at once, using free cells and empty tableaux")
+	var verbose *bool = fs.Bool("v", false, "print how each deal went")
+	fs.Parse(args)
+
+	ranges, err := parseSeeds(*seeds)
+	if err != nil {
+		return err
+	}
+	if *player != "hints" && *player != "solver" {
+		return fmt.Errorf("unknown player %s", *player)
+	}
+	if core.NewBaize(*variant, nil) == nil {
+		return fmt.Errorf("do not know how to play %s; gosol-cli list shows the variants", *variant)
+	}
+	log.SetOutput(io.Discard) // dealing is chatty
+
+	var options core.Options = core.Options{PowerMoves: *powerMoves}
+	var jobs chan uint64 = make(chan uint64)
+	var results chan result = make(chan result)
+	var wg sync.WaitGroup
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for seed := range jobs {
+				results <- playDeal(*variant, seed, options, *player, *maxMoves, *maxNodes)
+			}
+		}()
+	}
+	go func() {
+		for _, r := range ranges {
+			for seed := r.first; ; seed++ {
+				jobs <- seed
+				if seed == r.last {
+					break // not seed <= r.last, which is always true when r.last is the largest deal number
+				}
+			}
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	var start time.Time = time.Now()
+	var played, won, moves, wonMoves, percent int
+	for r := range results {
+		if r.err != nil {
+			fmt.Fprintf(os.Stderr, "deal %d: %s\n", r.seed, r.err)
+			continue
+		}
+		played++
+		moves += r.moves
+		percent += r.percent
+		if r.won {
+			won++
+			wonMoves += r.moves
+		}
+		if *verbose {
+			var outcome string = "lost"
+			if r.won {
+				outcome = "won"
+			}
+			fmt.Printf("deal %d %s in %d moves, %d%% complete, %s\n", r.seed, outcome, r.moves, r.percent, r.duration.Round(time.Millisecond))
+		}
+	}
+	var elapsed time.Duration = time.Since(start)
+	if played == 0 {
+		return fmt.Errorf("no deals played")
+	}
+	fmt.Printf("%s, %s player: %d deals, %d won (%.1f%%), %.1f moves a deal", *variant, *player, played, won, 100*float64(won)/float64(played), float64(moves)/float64(played))
+	if won > 0 {
+		fmt.Printf(", %.1f moves a win", float64(wonMoves)/float64(won))
+	}
+	fmt.Printf(", %d%% complete on average, %s (%s a deal)\n", percent/played, elapsed.Round(time.Millisecond), (elapsed / time.Duration(played)).Round(time.Microsecond))
+	return nil
+}
+
+// playDeal plays one deal of variant with the player chosen
+func playDeal(variant string, seed uint64, options core.Options, player string, maxMoves, maxNodes int) result {
+	var start time.Time = time.Now()
+	var r result = result{seed: seed}
+	b, err := newBaize(variant, seed, &options)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	if player == "solver" {
+		if maxNodes == 0 {
+			maxNodes = solver.DefaultMaxNodes(b)
+		}
+		r.moves, r.err = solver.SolvePlay(b, maxNodes)
+	} else {
+		r.moves = solver.AutoPlay(b, maxMoves)
+	}
+	r.won, r.percent, r.duration = b.Complete(), b.PercentComplete(), time.Since(start)
+	return r
+}
+
+// seedRange is the deal numbers first to last, inclusive
+type seedRange struct {
+	first, last uint64
+}
+
+// maxSeeds is the most deals one play command will play
+const maxSeeds = 10000000
+
+// parseSeeds reads a list of deal numbers and ranges of them, eg "1-100,200"
+func parseSeeds(s string) ([]seedRange, error) {
+	var ranges []seedRange
+	var count uint64
+	for _, part := range strings.Split(s, ",") {
+		var from, to string = part, part
+		if i := strings.Index(part, "-"); i != -1 {
+			from, to = part[:i], part[i+1:]
+		}
+		first, err := strconv.ParseUint(strings.TrimSpace(from), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad deal number in %s", part)
+		}
+		last, err := strconv.ParseUint(strings.TrimSpace(to), 10, 64)
+		if err != nil || last < first {
+			return nil, fmt.Errorf("bad deal number in %s", part)
+		}
+		if last-first >= maxSeeds || count+last-first+1 > maxSeeds {
+			return nil, fmt.Errorf("more than %d deals in %s", maxSeeds, s)
+		}
+		count += last - first + 1
+		ranges = append(ranges, seedRange{first, last})
+	}
+	return ranges, nil
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"oddstream.games/gosol/cardid"
+	"oddstream.games/gosol/core"
+	"oddstream.games/gosol/solver"
+)
+
+const termHelp = `Piles are named S stock, W waste, C cell, F foundation, T tableau, R reserve, D discard,
+numbered from 1 if there is more than one, eg T3. Type
+  T1 F2     to move cards from T1 to F2; as many as will go, or 3 T1 F2 for three cards
+  T1        to tap the top card of T1 (S deals from the stock)
+  u, y      to undo or redo
+  h         for a hint
+  c         to collect cards to the foundations
+  r, n      to restart the deal, or start a new one
+  q         to quit`
+
+// term plays a game in a terminal, drawing the piles as text and reading moves typed by the player
+type term struct {
+	b        *core.Baize
+	names    []string
+	plain    bool     // no colors, and do not clear the screen
+	messages []string // toasts from the game, shown under the piles
+	out      io.Writer
+}
+
+// ToastInfo is told things the player should know
+func (t *term) ToastInfo(s string) {
+	t.messages = append(t.messages, s)
+}
+
+// ToastError is told why a move cannot be made
+func (t *term) ToastError(s string) {
+	t.messages = append(t.messages, s)
+}
+
+func playTerm(args []string) error {
+	fs := flag.NewFlagSet("term", flag.ExitOnError)
+	var variant *string = fs.String("variant", "Klondike", "the variant to play")
+	var seed *uint64 = fs.Uint64("seed", 0, "the deal number; 0 for a random one")
+	var plain *bool = fs.Bool("plain", false, "do not use colors or clear the screen")
+	var autoCollect *bool = fs.Bool("autocollect", false, "move cards to the foundations after every move")
+	fs.Parse(args)
+
+	log.SetOutput(io.Discard) // dealing is chatty
+	if *seed == 0 {
+		*seed = core.NewSeed()
+	}
+	b, err := newBaize(*variant, *seed, &core.Options{PowerMoves: true, AutoCollect: *autoCollect})
+	if err != nil {
+		return err
+	}
+	t := &term{b: b, names: b.PileNames(), plain: *plain, out: os.Stdout}
+	b.SetNotifier(t)
+	t.ToastInfo("Type ? for help")
+
+	var in *bufio.Scanner = bufio.NewScanner(os.Stdin)
+	for {
+		t.draw()
+		fmt.Fprint(t.out, "> ")
+		if !in.Scan() {
+			return in.Err()
+		}
+		if !t.command(strings.TrimSpace(in.Text())) {
+			return nil
+		}
+	}
+}
+
+// command does what the player typed, returning false if they want to quit
+func (t *term) command(line string) bool {
+	switch strings.ToLower(line) {
+	case "":
+	case "q", "quit", "exit":
+		return false
+	case "?", "help":
+		t.messages = append(t.messages, termHelp)
+	case "u", "undo":
+		if err := t.b.Undo(); err != nil {
+			t.ToastError(err.Error())
+		}
+	case "y", "redo":
+		if err := t.b.Redo(); err != nil {
+			t.ToastError(err.Error())
+		}
+	case "r", "restart":
+		if err := t.b.RestartDeal(); err != nil {
+			t.ToastError(err.Error())
+		}
+	case "n", "new":
+		t.b.NewDeal(core.NewSeed())
+	case "c", "collect":
+		var crc uint32 = t.b.CRC()
+		t.b.Collect2()
+		if crc == t.b.CRC() {
+			t.ToastError("Nothing to collect")
+		}
+		t.afterMove()
+	case "h", "hint":
+		t.hint()
+	default:
+		t.move(line)
+	}
+	return true
+}
+
+// move makes a move typed as pile names, with an optional number of cards, eg "T1 F2", "3 T1-T4" or "S"
+func (t *term) move(line string) {
+	var fields []string = strings.Fields(strings.ToUpper(strings.ReplaceAll(line, "-", " ")))
+	var count int
+	if len(fields) > 0 {
+		if n, err := strconv.Atoi(fields[0]); err == nil {
+			count, fields = n, fields[1:]
+		}
+	}
+	if len(fields) == 0 {
+		t.ToastError("Type the pile to move from, then the pile to move to; type ? for help")
+		return
+	}
+	var piles []*core.Pile
+	for _, f := range fields {
+		p := t.pile(f)
+		if p == nil {
+			t.ToastError(fmt.Sprintf("No pile called %s; type ? for help", f))
+			return
+		}
+		piles = append(piles, p)
+	}
+	switch len(piles) {
+	case 1:
+		var crc uint32 = t.b.CRC()
+		if piles[0].Empty() {
+			t.b.PileTapped(piles[0])
+		} else {
+			t.b.TailTapped([]*core.Card{piles[0].Peek()})
+		}
+		if crc != t.b.CRC() {
+			t.b.AfterUserMove()
+			t.b.AfterAfterUserMove()
+		}
+	case 2:
+		if err := t.moveTail(piles[0], piles[1], count); err != nil {
+			t.ToastError(err.Error())
+			return
+		}
+		t.b.AfterUserMove()
+		t.b.AfterAfterUserMove()
+	default:
+		t.ToastError("Type the pile to move from, then the pile to move to; type ? for help")
+		return
+	}
+	t.afterMove()
+}
+
+// moveTail moves count cards from src to dst or, if count is 0, as many face up cards as will go,
+// returning the reason the game gives if none will
+func (t *term) moveTail(src, dst *core.Pile, count int) error {
+	var cards []*core.Card = src.Cards()
+	if count > len(cards) || len(cards) == 0 {
+		return fmt.Errorf("Not enough cards in %s", t.name(src))
+	}
+	if count > 0 {
+		return t.b.MoveTailTo(cards[len(cards)-count:], dst)
+	}
+	var err error
+	for n := len(cards); n > 0; n-- {
+		if cards[len(cards)-n].Prone() {
+			continue
+		}
+		if err = t.b.MoveTailTo(cards[len(cards)-n:], dst); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// afterMove tells the player what they can do next
+func (t *term) afterMove() {
+	if t.b.Complete() {
+		t.ToastInfo("Complete! n for a new deal")
+	} else if t.b.Conformant() {
+		t.ToastInfo("c to collect the cards to the foundations")
+	} else if t.b.Moves() == 0 {
+		t.ToastError("No movable cards")
+	}
+}
+
+// hint shows the best move
+func (t *term) hint() {
+	var hints []solver.Move = solver.Hints(t.b)
+	if len(hints) == 0 {
+		t.ToastError("There are no moves")
+		return
+	}
+	var m solver.Move = hints[0]
+	if m.Tap() {
+		t.ToastInfo("Try " + t.name(m.Src))
+	} else {
+		var count string
+		if m.Count > 1 {
+			count = fmt.Sprintf("%d ", m.Count)
+		}
+		t.ToastInfo(fmt.Sprintf("Try %s%s %s", count, t.name(m.Src), t.name(m.Dst)))
+	}
+}
+
+// pile finds a pile by name
+func (t *term) pile(name string) *core.Pile {
+	for i, n := range t.names {
+		if n == name || n+"1" == name {
+			return t.b.Piles()[i]
+		}
+	}
+	return nil
+}
+
+func (t *term) name(p *core.Pile) string {
+	for i, bp := range t.b.Piles() {
+		if bp == p {
+			return t.names[i]
+		}
+	}
+	return "?"
+}
+
+// card writes a card three characters wide, face down cards as ##, red cards in red
+func (t *term) card(c *core.Card) string {
+	if c == nil {
+		return "-- "
+	}
+	if c.Prone() {
+		return "## "
+	}
+	var s string = fmt.Sprintf("%-3s", c.ID().Notation())
+	if !t.plain && (c.Suit() == cardid.HEART || c.Suit() == cardid.DIAMOND) {
+		s = "\x1b[31m" + s + "\x1b[0m"
+	}
+	return s
+}
+
+// draw writes the piles, tableaux as columns and the rest as their top card,
+// then any messages from the game since last time
+func (t *term) draw() {
+	var sb strings.Builder
+	if !t.plain {
+		sb.WriteString("\x1b[H\x1b[2J")
+	}
+	fmt.Fprintf(&sb, "%s deal %d, %d moves, %d%% complete", t.b.Variant(), t.b.Seed(), t.b.UndoLen()-1, t.b.PercentComplete())
+	if t.b.Recycles() > 0 {
+		fmt.Fprintf(&sb, ", %d recycles left", t.b.Recycles())
+	}
+	sb.WriteString("\n\n")
+
+	var tableaux []int
+	for _, category := range []string{"Stock", "Waste", "Reserve", "Cell", "Foundation", "Discard"} {
+		var line []string
+		for i, p := range t.b.Piles() {
+			if p.Hidden() || p.Category() != category {
+				continue
+			}
+			var s string = t.names[i] + ": " + t.card(p.Peek())
+			if p.Len() > 1 || category == "Stock" {
+				s += fmt.Sprintf("(%d)", p.Len())
+			}
+			line = append(line, s)
+		}
+		if len(line) > 0 {
+			sb.WriteString(strings.Join(line, "  ") + "\n")
+		}
+	}
+	for i, p := range t.b.Piles() {
+		if !p.Hidden() && p.Category() == "Tableau" {
+			tableaux = append(tableaux, i)
+		}
+	}
+	if len(tableaux) > 0 {
+		sb.WriteString("\n")
+		var rows int
+		for _, i := range tableaux {
+			fmt.Fprintf(&sb, "%-4s", t.names[i])
+			if n := t.b.Piles()[i].Len(); n > rows {
+				rows = n
+			}
+		}
+		sb.WriteString("\n")
+		for row := 0; row < rows; row++ {
+			for _, i := range tableaux {
+				var cards []*core.Card = t.b.Piles()[i].Cards()
+				if row < len(cards) {
+					sb.WriteString(t.card(cards[row]) + " ")
+				} else {
+					sb.WriteString("    ")
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("\n")
+	for _, m := range t.messages {
+		sb.WriteString(m + "\n")
+	}
+	t.messages = nil
+	fmt.Fprint(t.out, sb.String())
+}
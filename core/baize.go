@@ -0,0 +1,590 @@
+// Package core provides the rules of a polymorphic solitaire engine:
+// piles, cards, variant scripts and undo, with no rendering, sound or input.
+package core
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"log"
+	"reflect"
+
+	"oddstream.games/gosol/util"
+)
+
+// DebugMode is a boolean that turns on extra logging
+var DebugMode bool = false
+
+// Options holds the user preferences that change the rules of the game
+type Options struct {
+	// Capitals to emit to json
+	PowerMoves               bool
+	SafeCollect, AutoCollect bool
+	MirrorBaize              bool
+}
+
+// Notifier is told about things the player should know,
+// eg "No more recycles". It is optional.
+type Notifier interface {
+	ToastInfo(string)
+	ToastError(string)
+}
+
+// Baize object describes the state of one game of one variant
+type Baize struct {
+	variant   string
+	piles     []*Pile
+	cardCount int
+	recycles  int
+	bookmarks []Bookmark
+	seed      uint64 // the deal number; 0 if not known (eg game loaded from an old save file)
+	shuffle   ShuffleType
+	winnable  bool // the deal was found to be winnable before it was dealt
+	script    Scripter
+	undoStack []*UndoRecord // the positions of the branch being played, up to the current one
+	current   *SavableBaize // the last position in undoStack
+	branches  []*branch     // every line of play from the deal
+	branch    int           // the branch being played
+	moves     int           // number of possible (not useless) moves
+	fmoves    int           // number of possible moves to a Foundation (for enabling Collect button)
+	options   *Options
+	notifier  Notifier
+}
+
+// NewBaize is the factory func for Baize objects.
+// Each Baize gets its own copy of the variant script, so many can exist at once.
+func NewBaize(variant string, options *Options) *Baize {
+	var proto Scripter
+	var ok bool
+	if proto, ok = Variants[variant]; !ok {
+		log.Printf("do not know how to play " + variant)
+		return nil
+	}
+	if options == nil {
+		options = &Options{PowerMoves: true}
+	}
+	b := &Baize{variant: variant, script: newScript(proto), options: options}
+	b.script.setBaize(b)
+	return b
+}
+
+// newScript makes a shallow copy of a Variants prototype
+func newScript(proto Scripter) Scripter {
+	v := reflect.ValueOf(proto).Elem()
+	cp := reflect.New(v.Type())
+	cp.Elem().Set(v)
+	return cp.Interface().(Scripter)
+}
+
+// SetNotifier sets the object that will be told things the player should know
+func (b *Baize) SetNotifier(n Notifier) {
+	b.notifier = n
+}
+
+func (b *Baize) toastInfo(str string) {
+	if b.notifier != nil {
+		b.notifier.ToastInfo(str)
+	}
+}
+
+func (b *Baize) toastError(str string) {
+	if b.notifier != nil {
+		b.notifier.ToastError(str)
+	}
+}
+
+func (b *Baize) Variant() string {
+	return b.variant
+}
+
+func (b *Baize) Script() Scripter {
+	return b.script
+}
+
+func (b *Baize) Options() *Options {
+	return b.options
+}
+
+func (b *Baize) Piles() []*Pile {
+	return b.piles
+}
+
+func (b *Baize) CardCount() int {
+	return b.cardCount
+}
+
+// homeCount returns the number of cards that finish on a Foundation or Discard; jokers stay behind
+func (b *Baize) homeCount() int {
+	return b.cardCount - b.script.Packs()*b.script.Jokers()
+}
+
+// Moves returns the number of possible (not useless) moves
+func (b *Baize) Moves() int {
+	return b.moves
+}
+
+// FMoves returns the number of possible moves to a Foundation
+func (b *Baize) FMoves() int {
+	return b.fmoves
+}
+
+// Seed returns the number of the current deal
+func (b *Baize) Seed() uint64 {
+	return b.seed
+}
+
+// Winnable returns true if this deal was found to be winnable before it was dealt
+func (b *Baize) Winnable() bool {
+	return b.winnable
+}
+
+// MarkWinnable records that this deal was found to be winnable before it was dealt
+func (b *Baize) MarkWinnable() {
+	b.winnable = true
+	for _, rec := range b.undoStack {
+		if rec.Keyframe != nil {
+			rec.Keyframe.Winnable = true
+		}
+	}
+	if b.current != nil {
+		b.current.Winnable = true
+	}
+}
+
+// Shuffle returns the algorithm that turned the deal number into the current deal
+func (b *Baize) Shuffle() ShuffleType {
+	return b.shuffle
+}
+
+func (b *Baize) CRC() uint32 {
+	var lens []byte
+	for _, p := range b.piles {
+		lens = append(lens, byte(p.Len()))
+	}
+	return crc32.ChecksumIEEE(lens)
+}
+
+func (b *Baize) AddPile(pile *Pile) {
+	b.piles = append(b.piles, pile)
+}
+
+// NewDeal restarts current variant (ie no pile building) with a new seed
+func (b *Baize) NewDeal(seed uint64) {
+	b.deal(seed, SHUFFLE_GOSOL)
+}
+
+// NewMicrosoftDeal restarts current variant with the layout that
+// Microsoft FreeCell gives the same deal number
+func (b *Baize) NewMicrosoftDeal(number uint64) error {
+	if err := b.MicrosoftDealError(number); err != nil {
+		return err
+	}
+	b.deal(number, SHUFFLE_MICROSOFT)
+	return nil
+}
+
+// MicrosoftDealError returns an error explaining why the current variant
+// cannot be dealt with this Microsoft deal number
+func (b *Baize) MicrosoftDealError(number uint64) error {
+	if !b.script.MicrosoftDeals() {
+		return fmt.Errorf("%s does not have Microsoft deal numbers", b.variant)
+	}
+	if number < 1 || number > MaxMicrosoftDeal {
+		return fmt.Errorf("Microsoft deal numbers are 1 to %d", MaxMicrosoftDeal)
+	}
+	return nil
+}
+
+func (b *Baize) deal(seed uint64, shuffle ShuffleType) {
+	b.Reset()
+	b.seed = seed
+	b.shuffle = shuffle
+
+	for _, p := range b.piles {
+		p.Reset()
+	}
+
+	b.script.forgetCards()
+	// Stock.Fill() needs parameters
+	packs := b.script.Packs()
+	suits := b.script.Suits()
+	b.cardCount = b.script.Stock().Fill(packs, suits, b.script.Jokers())
+	switch b.shuffle {
+	case SHUFFLE_MICROSOFT:
+		if err := arrangeMicrosoftStock(b.script.Stock(), b.seed); err != nil {
+			log.Panic(err)
+		}
+	default:
+		b.script.Stock().Shuffle(b.seed)
+	}
+	b.script.StartGame()
+	b.UndoPush()
+	b.FindDestinations()
+}
+
+func (b *Baize) MirrorSlots() {
+	/*
+		0 1 2 3 4 5
+		5 4 3 2 1 0
+
+		0 1 2 3 4
+		4 3 2 1 0
+	*/
+	var minX int = 32767
+	var maxX int = 0
+	for _, p := range b.piles {
+		if p.Slot().X < 0 {
+			continue // ignore hidden pile
+		}
+		if p.Slot().X < minX {
+			minX = p.Slot().X
+		}
+		if p.Slot().X > maxX {
+			maxX = p.Slot().X
+		}
+	}
+	for _, p := range b.piles {
+		slot := p.Slot()
+		if slot.X < 0 {
+			continue // ignore hidden pile
+		}
+		p.SetSlot(image.Point{X: maxX - slot.X + minX, Y: slot.Y})
+		switch p.FanType() {
+		case FAN_RIGHT:
+			p.SetFanType(FAN_LEFT)
+		case FAN_LEFT:
+			p.SetFanType(FAN_RIGHT)
+		case FAN_RIGHT3:
+			p.SetFanType(FAN_LEFT3)
+		case FAN_LEFT3:
+			p.SetFanType(FAN_RIGHT3)
+		}
+	}
+}
+
+func (b *Baize) Reset() {
+	b.undoStack = nil
+	b.current = nil
+	b.branches = nil
+	b.branch = 0
+	b.bookmarks = nil
+	b.recycles = 0
+	b.winnable = false
+	// leave script intact
+}
+
+// StartFreshGame resets Baize and starts a new game with a new seed
+func (b *Baize) StartFreshGame(seed uint64) {
+	b.Reset()
+	b.seed = seed
+	b.shuffle = SHUFFLE_GOSOL
+	b.piles = []*Pile{}
+	b.script.BuildPiles()
+	if b.options.MirrorBaize {
+		b.MirrorSlots()
+	}
+	b.script.StartGame()
+	b.UndoPush()
+	b.FindDestinations()
+}
+
+// Copy returns a new Baize playing the same variant from the same position,
+// without any history, so it can be played (eg by a solver) without changing this one
+func (b *Baize) Copy() *Baize {
+	var options Options = *b.options
+	cp := NewBaize(b.variant, &options)
+	cp.seed = b.seed
+	cp.shuffle = b.shuffle
+	cp.piles = []*Pile{}
+	cp.script.BuildPiles()
+	if cp.options.MirrorBaize {
+		cp.MirrorSlots()
+	}
+	cp.SetUndoStack([]*SavableBaize{b.UndoPeek()})
+	return cp
+}
+
+// SetUndoStack replaces the history of this game with a list of positions, eg from an old save file,
+// and moves the cards to where the last position says they are
+func (b *Baize) SetUndoStack(undoStack []*SavableBaize) error {
+	if len(undoStack) == 0 {
+		return errors.New("Saved game has no positions")
+	}
+	b.undoStack = nil
+	b.current = nil
+	b.branches = nil
+	for _, sav := range undoStack {
+		b.updateFromSavable(sav)
+		b.UndoPush()
+	}
+	b.bookmarks = nil
+	if bm := undoStack[len(undoStack)-1].Bookmark; bm > 0 && bm <= len(undoStack) {
+		b.bookmarks = []Bookmark{b.legacyBookmark(bm)}
+	}
+	b.FindDestinations()
+	return nil
+}
+
+// AfterUserMove lets the script tidy up, then records the new position
+func (b *Baize) AfterUserMove() {
+	b.script.AfterMove()
+	b.UndoPush()
+	b.FindDestinations()
+}
+
+// AfterAfterUserMove checks for and executes an automatic collect.
+// Kept as separated-out function at the moment, in case this
+// creates a horrible recursive loop
+func (b *Baize) AfterAfterUserMove() {
+	if b.fmoves > 0 && b.options.AutoCollect {
+		b.Collect2()
+	}
+}
+
+// MoveTailTo moves a tail of cards to dst, if the rules allow it,
+// as if the player had dragged the tail there.
+// Returns an error explaining why the move is not allowed.
+func (b *Baize) MoveTailTo(tail []*Card, dst *Pile) error {
+	var card *Card = tail[0]
+	var src *Pile = card.Owner()
+	if ok, err := src.CanMoveTail(tail); !ok {
+		return err
+	}
+	if ok, err := dst.CanAcceptTail(tail); !ok {
+		return err
+	}
+	if src == dst {
+		return nil
+	}
+	if ok, err := b.script.TailMoveError(tail); !ok {
+		return err
+	}
+	if len(tail) == 1 {
+		MoveCard(src, dst)
+	} else {
+		MoveTail(card, dst)
+	}
+	return nil
+}
+
+// TailTapped offers a tapped tail to the script first,
+// to implement things like Stock.TailTapped;
+// if the script doesn't want to do anything, it can call pile.vtable.TailTapped
+// which will either ignore it (eg Foundation, Discard)
+// or use Pile.DefaultTailTapped
+func (b *Baize) TailTapped(tail []*Card) {
+	b.script.TailTapped(tail)
+}
+
+// PileTapped offers a tapped (empty) pile to the script
+func (b *Baize) PileTapped(pile *Pile) {
+	b.script.PileTapped(pile)
+}
+
+// ForeachCard applys a function to each card
+func (b *Baize) ForeachCard(fn func(*Card)) {
+	for _, p := range b.piles {
+		for _, c := range p.cards {
+			fn(c)
+		}
+	}
+}
+
+func (b *Baize) powerMoves(pDraggingTo *Pile) int {
+	// (1 + number of empty freecells) * 2 ^ (number of empty columns)
+	// see http://ezinearticles.com/?Freecell-PowerMoves-Explained&id=104608
+	// and http://www.solitairecentral.com/articles/FreecellPowerMovesExplained.html
+	var emptyCells, emptyCols int
+	for _, p := range b.piles {
+		if p.Empty() {
+			switch p.vtable.(type) {
+			case *Cell:
+				emptyCells++
+			case *Tableau:
+				if p.Label() == "" && p != pDraggingTo {
+					// 'If you are moving into an empty column, then the column you are moving into does not count as empty column.'
+					emptyCols++
+				}
+			}
+		}
+	}
+	// 2^1 == 2, 2^0 == 1, 2^-1 == 0.5
+	n := (1 + emptyCells) * util.Pow(2, emptyCols)
+	return n
+}
+
+// DoingSafeCollect return true if we are doing safe collect
+// and the safe ordinal to collect next
+func (b *Baize) DoingSafeCollect() (bool, int) {
+	if !b.options.SafeCollect {
+		return false, 0
+	}
+	if !b.script.SafeCollect() {
+		return false, 0
+	}
+	var fs []*Pile = b.script.Foundations()
+	if fs == nil {
+		return false, 0
+	}
+	var f0 *Pile = fs[0]
+	if f0 == nil {
+		return false, 0
+	}
+	if f0.Label() != "A" {
+		return false, 0 // eg Duchess
+	}
+	var lowest int = 99
+	for _, f := range fs {
+		if f.Empty() {
+			// it's okay to collect aces and twos to start with
+			return true, 2
+		}
+		var card *Card = f.Peek()
+		if card.Ordinal() < lowest {
+			lowest = card.Ordinal()
+		}
+	}
+	return true, lowest + 1
+}
+
+// collectFromPile is a helper function for Collect2()
+func (b *Baize) collectFromPile(pile *Pile) int {
+	if pile == nil {
+		return 0
+	}
+	var cardsMoved int = 0
+	for _, fp := range b.script.Foundations() {
+		for {
+			var card *Card = pile.Peek()
+			if card == nil {
+				return cardsMoved
+			}
+			ok, _ := fp.vtable.CanAcceptTail([]*Card{card})
+			if !ok {
+				break // done with this foundation, try another
+			}
+			if ok, safeOrd := b.DoingSafeCollect(); ok {
+				if card.Ordinal() > safeOrd {
+					break // done with this foundation, try another
+				}
+			}
+			MoveCard(pile, fp)
+			b.AfterUserMove() // does an undoPush()
+			b.AfterAfterUserMove()
+			cardsMoved += 1
+		}
+	}
+	return cardsMoved
+}
+
+// Collect2 should be exactly the same as the user tapping repeatedly on the
+// waste, cell, reserve and tableau piles.
+// nb there is no collecting to discard piles, they are optional and presence of
+// cards in them does not signify a complete game.
+// It's called Collect2 because it's the third or fourth rewrite of a
+// basic and seemingly simple function.
+func (b *Baize) Collect2() {
+	for {
+		var cardsMoved int = b.collectFromPile(b.script.Waste())
+		for _, pile := range b.script.Cells() {
+			cardsMoved += b.collectFromPile(pile)
+		}
+		for _, pile := range b.script.Reserves() {
+			cardsMoved += b.collectFromPile(pile)
+		}
+		for _, pile := range b.script.Tableaux() {
+			cardsMoved += b.collectFromPile(pile)
+		}
+		if cardsMoved == 0 {
+			break
+		}
+	}
+}
+
+func (b *Baize) MaxSlotX() int {
+	var maxX int
+	for _, p := range b.piles {
+		if p.Slot().X > maxX {
+			maxX = p.Slot().X
+		}
+	}
+	return maxX
+}
+
+func (b *Baize) PercentComplete() int {
+	var pairs, unsorted, percent int
+	for _, p := range b.piles {
+		if p.Len() > 1 {
+			pairs += p.Len() - 1
+		}
+		unsorted += p.vtable.UnsortedPairs()
+	}
+	percent = (int)(100.0 - util.MapValue(float64(unsorted), 0, float64(pairs), 0.0, 100.0))
+	return percent
+}
+
+func (b *Baize) Recycles() int {
+	return b.recycles
+}
+
+func (b *Baize) SetRecycles(recycles int) {
+	b.recycles = recycles
+}
+
+func (b *Baize) Conformant() bool {
+	for _, p := range b.piles {
+		if !p.vtable.Conformant() {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Baize) Complete() bool {
+	return b.script.Complete()
+}
+
+// Undo reverts the Baize state to it's previous state
+func (b *Baize) Undo() error {
+	if len(b.undoStack) < 2 {
+		return errors.New("Nothing to undo")
+	}
+	if b.Complete() {
+		return errors.New("Cannot undo a completed game") // otherwise the stats can be cooked
+	}
+	var saved bool = b.options.AutoCollect
+	b.options.AutoCollect = false
+	b.undoTo(len(b.undoStack) - 1)
+	b.options.AutoCollect = saved
+	return nil
+}
+
+// Redo makes the move that was last undone again
+func (b *Baize) Redo() error {
+	if b.RedoLen() == 0 {
+		return errors.New("Nothing to redo")
+	}
+	if b.Complete() {
+		return errors.New("Cannot redo a completed game")
+	}
+	b.retime(len(b.undoStack))
+	b.undoTo(len(b.undoStack) + 1)
+	return nil
+}
+
+// RestartDeal goes back to the deal, forgetting the moves made, the branches and the bookmarks;
+// use Undo to go back and keep the moves to redo
+func (b *Baize) RestartDeal() error {
+	if b.Complete() {
+		return errors.New("Cannot restart a completed game") // otherwise the stats can be cooked
+	}
+	b.undoTo(1)
+	b.branches = []*branch{{records: b.undoStack[:1:1], percent: b.PercentComplete()}}
+	b.branch = 0
+	b.undoStack = b.branches[0].records
+	b.bookmarks = nil
+	return nil
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+)
+
+func countCards(b *Baize) int {
+	var n int
+	for _, p := range b.Piles() {
+		n += p.Len()
+	}
+	return n
+}
+
+// TestDealAllVariants deals every variant without a screen, sound or user
+func TestDealAllVariants(t *testing.T) {
+	for name := range Variants {
+		b := NewBaize(name, nil)
+		if b == nil {
+			t.Fatalf("cannot create %s", name)
+		}
+		b.StartFreshGame(NewSeed())
+		b.NewDeal(NewSeed())
+		if n := countCards(b); n != b.CardCount() {
+			t.Errorf("%s: dealt %d cards, expected %d", name, n, b.CardCount())
+		}
+		if len(b.UndoStack()) != 1 {
+			t.Errorf("%s: undo stack has %d entries after deal", name, len(b.UndoStack()))
+		}
+		if !b.IsSavableStackOk(b.UndoStack()) {
+			t.Errorf("%s: undo stack is not ok", name)
+		}
+		b.Collect2()
+		if n := countCards(b); n != b.CardCount() {
+			t.Errorf("%s: %d cards after collect, expected %d", name, n, b.CardCount())
+		}
+	}
+}
+
+// TestBaizesAreIndependent checks that two games of the same variant do not share piles
+func TestBaizesAreIndependent(t *testing.T) {
+	b1 := NewBaize("Klondike", nil)
+	b2 := NewBaize("Klondike", nil)
+	b1.StartFreshGame(NewSeed())
+	b2.StartFreshGame(NewSeed())
+	if b1.Script().Stock() == b2.Script().Stock() {
+		t.Error("two Klondike games share a stock")
+	}
+	if b1.Script().Stock().Baize() != b1 {
+		t.Error("stock does not belong to its baize")
+	}
+}
+
+// TestSeededDealsRepeat checks that a deal number always gives the same layout
+func TestSeededDealsRepeat(t *testing.T) {
+	for _, name := range []string{"Klondike", "Freecell", "Spider Two Suits"} {
+		b1 := NewBaize(name, nil)
+		b2 := NewBaize(name, nil)
+		b1.StartFreshGame(12345)
+		b2.StartFreshGame(NewSeed())
+		b2.NewDeal(12345)
+		s1, s2 := b1.UndoPeek(), b2.UndoPeek()
+		if s1.Seed != 12345 || s2.Seed != 12345 {
+			t.Errorf("%s: seed not saved", name)
+		}
+		for i := range s1.Piles {
+			if fmt.Sprint(s1.Piles[i].Cards) != fmt.Sprint(s2.Piles[i].Cards) {
+				t.Errorf("%s: deal 12345 differs in %s", name, s1.Piles[i].Category)
+			}
+		}
+	}
+}
+
+// TestSeedsUseAllBits checks that deal numbers a multiple of 2^31-1 apart give different deals
+func TestSeedsUseAllBits(t *testing.T) {
+	b1 := NewBaize("Klondike", nil)
+	b2 := NewBaize("Klondike", nil)
+	b1.StartFreshGame(5)
+	b2.StartFreshGame(5 + 0x7FFFFFFF)
+	if fmt.Sprint(b1.Position().Piles) == fmt.Sprint(b2.Position().Piles) {
+		t.Error("deals 5 and 2147483652 are the same")
+	}
+}
+
+// TestCopy checks that a copy of a game starts in the same place and goes its own way
+func TestCopy(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame(NewSeed())
+	cp := b.Copy()
+	if fmt.Sprint(b.Position().Piles[0].Cards) != fmt.Sprint(cp.Position().Piles[0].Cards) {
+		t.Fatal("copy has a different stock")
+	}
+	cp.TailTapped([]*Card{cp.Script().Stock().Peek()})
+	if b.Script().Stock().Len() == cp.Script().Stock().Len() {
+		t.Error("dealing from the copy changed the original")
+	}
+	if len(cp.UndoStack()) != 1 {
+		t.Errorf("copy has %d positions of history", len(cp.UndoStack()))
+	}
+}
+
+// TestMarkWinnable checks that a winnable deal stays winnable through undo and saving
+func TestMarkWinnable(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame(NewSeed())
+	b.NewDeal(12345)
+	b.MarkWinnable()
+	b.TailTapped([]*Card{b.Script().Stock().Peek()})
+	b.AfterUserMove()
+	if err := b.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if !b.Winnable() || !b.UndoPeek().Winnable {
+		t.Error("undo forgot the deal was winnable")
+	}
+	b.NewDeal(12346)
+	if b.Winnable() {
+		t.Error("a new deal should not be winnable until marked")
+	}
+}
@@ -0,0 +1,99 @@
+package core
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Bookmark is a position the player wants to be able to go back to
+type Bookmark struct {
+	Name    string
+	Branch  int `json:",omitempty"` // the branch the position is in
+	Depth   int // number of positions in the branch up to and including this one
+	Percent int `json:",omitempty"` // how complete the game was
+}
+
+// Moves returns the number of moves made to get to the bookmarked position
+func (bm Bookmark) Moves() int {
+	return bm.Depth - 1
+}
+
+// Bookmarks returns the bookmarks of this game, in the order they were made
+func (b *Baize) Bookmarks() []Bookmark {
+	return append([]Bookmark{}, b.bookmarks...)
+}
+
+func (b *Baize) findBookmark(name string) int {
+	for i, bm := range b.bookmarks {
+		if bm.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddBookmark bookmarks the current position, naming it after the number of moves made,
+// and returns the name
+func (b *Baize) AddBookmark() (string, error) {
+	if b.Complete() {
+		return "", errors.New("Cannot bookmark a completed game") // otherwise the stats can be cooked
+	}
+	var depth int = len(b.undoStack)
+	for _, bm := range b.bookmarks {
+		if bm.Branch == b.branch && bm.Depth == depth {
+			return "", fmt.Errorf("Position is already bookmarked as %s", bm.Name)
+		}
+	}
+	// the same number of moves in another branch gets a letter to tell them apart
+	var name string = fmt.Sprintf("Move %d", depth-1)
+	for suffix := 'b'; b.findBookmark(name) != -1; suffix++ {
+		name = fmt.Sprintf("Move %d%c", depth-1, suffix)
+	}
+	b.bookmarks = append(b.bookmarks, Bookmark{Name: name, Branch: b.branch, Depth: depth, Percent: b.PercentComplete()})
+	return name, nil
+}
+
+// GotoBookmark goes back (or forward) to a bookmarked position
+func (b *Baize) GotoBookmark(name string) error {
+	var i int = b.findBookmark(name)
+	if i == -1 {
+		return errors.New("No such bookmark")
+	}
+	if b.Complete() {
+		return errors.New("Cannot undo a completed game") // otherwise the stats can be cooked
+	}
+	b.goTo(b.bookmarks[i].Branch, b.bookmarks[i].Depth)
+	return nil
+}
+
+// DeleteBookmark forgets a bookmark; the position itself stays in the history
+func (b *Baize) DeleteBookmark(name string) error {
+	var i int = b.findBookmark(name)
+	if i == -1 {
+		return errors.New("No such bookmark")
+	}
+	b.bookmarks = append(b.bookmarks[:i], b.bookmarks[i+1:]...)
+	return nil
+}
+
+// setBookmarks replaces the bookmarks with ones loaded from file, dropping any that are not in the history
+func (b *Baize) setBookmarks(bookmarks []Bookmark) {
+	b.bookmarks = nil
+	for _, bm := range bookmarks {
+		if bm.Name == "" || b.findBookmark(bm.Name) != -1 || bm.Branch < 0 || bm.Branch >= len(b.branches) || bm.Depth < 1 || bm.Depth > len(b.branches[bm.Branch].records) {
+			continue
+		}
+		b.bookmarks = append(b.bookmarks, bm)
+	}
+}
+
+// legacyBookmark makes a bookmark for a position from an old save file, which only kept one
+func (b *Baize) legacyBookmark(depth int) Bookmark {
+	var playing, played int = b.branch, len(b.undoStack)
+	b.goTo(0, depth)
+	var bm Bookmark = Bookmark{Name: fmt.Sprintf("Move %d", depth-1), Depth: depth, Percent: b.PercentComplete()}
+	b.goTo(playing, played)
+	return bm
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestBookmarks checks going back to several bookmarks, in different branches,
+// after saving and loading too
+func TestBookmarks(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame(12345)
+	positions := playRandomMoves(b, 20, 3)
+	first, err := b.AddBookmark()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != "Move 20" {
+		t.Errorf("bookmark named %s", first)
+	}
+	if _, err := b.AddBookmark(); err == nil {
+		t.Error("bookmarked the same position twice")
+	}
+	for i := 0; i < 10; i++ {
+		b.Undo()
+	}
+	// keep trying until a move is made that differs from the one undone, so there are two branches,
+	// then play on to the same number of moves
+	for seed := int64(4); len(b.Branches()) == 1 || b.UndoLen() < len(positions); seed++ {
+		if seed == 100 {
+			t.Fatal("could not make another branch")
+		}
+		if len(b.Branches()) == 1 && b.RedoLen() < 10 {
+			b.Undo()
+		}
+		playRandomMoves(b, 1, seed)
+	}
+	second, _ := b.AddBookmark()
+	if second != "Move 20b" {
+		t.Errorf("bookmark in another branch named %s", second)
+	}
+	marked := b.Position()
+	playRandomMoves(b, 20, 5)
+
+	bytes, err := b.MarshalGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b2 := NewBaize("Klondike", nil)
+	b2.StartFreshGame(1)
+	if err := b2.UnmarshalGame(bytes); err != nil {
+		t.Fatal(err)
+	}
+	bookmarks := b.Bookmarks()
+	for _, bb := range []*Baize{b, b2} {
+		if !reflect.DeepEqual(bb.Bookmarks(), bookmarks) || len(bookmarks) != 2 {
+			t.Fatalf("bookmarks %v", bb.Bookmarks())
+		}
+		if err := bb.GotoBookmark(first); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(bb.UndoStack(), positions) {
+			t.Errorf("did not go back to %s", first)
+		}
+		if err := bb.GotoBookmark(second); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(bb.Position(), marked) || bb.UndoLen() != 21 {
+			t.Errorf("did not go back to %s", second)
+		}
+		if err := bb.DeleteBookmark(first); err != nil {
+			t.Fatal(err)
+		}
+		if bms := bb.Bookmarks(); len(bms) != 1 || bms[0].Name != second {
+			t.Errorf("bookmarks %v after deleting %s", bms, first)
+		}
+		if err := bb.GotoBookmark(first); err == nil {
+			t.Error("went to a deleted bookmark")
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"oddstream.games/gosol/cardid"
+)
+
+// Card object
+//
+// A core Card knows nothing of where it is drawn or how it moves;
+// that is left to the presentation layer, which finds its own
+// objects using the card's PackSuitOrdinal.
+type Card struct {
+	id         cardid.CardID
+	owningPile *Pile
+
+	// tap things
+	tapDestination *Pile
+	tapWeight      int
+}
+
+// NewCard is a factory for Card objects
+func NewCard(pack, suit, ordinal int) Card {
+	// a joker ID will be created by having NOSUIT (0) and ordinal == 0
+	return Card{id: cardid.NewCardID(pack, suit, ordinal)}
+}
+
+// String satisfies the Stringer interface (defined by fmt package)
+func (c *Card) String() string {
+	return c.id.String()
+}
+
+// ID returns the CardID of this card, including the prone flag
+func (c *Card) ID() cardid.CardID {
+	return c.id
+}
+
+func (c *Card) Owner() *Pile {
+	return c.owningPile
+}
+
+func (c *Card) SetOwner(p *Pile) {
+	c.owningPile = p
+}
+
+func (c *Card) Ordinal() int {
+	return c.id.Ordinal()
+}
+
+func (c *Card) Suit() int {
+	return c.id.Suit()
+}
+
+func (c *Card) Prone() bool {
+	return c.id.Prone()
+}
+
+func (c *Card) SetProne(prone bool) {
+	c.id = c.id.SetProne(prone)
+}
+
+func (c *Card) Black() bool {
+	return c.id.Black()
+}
+
+func (c *Card) Joker() bool {
+	return c.id.Joker()
+}
+
+// FlipUp turns the card face up
+func (c *Card) FlipUp() {
+	c.SetProne(false)
+}
+
+// FlipDown turns the card face down
+func (c *Card) FlipDown() {
+	c.SetProne(true)
+}
+
+// SetFlip turns the card over
+func (c *Card) SetFlip(prone bool) {
+	c.SetProne(prone)
+}
+
+// TapDestination returns the pile this card would go to if tapped,
+// as found by Baize.FindDestinations
+func (c *Card) TapDestination() *Pile {
+	return c.tapDestination
+}
+
+// TapWeight returns how good a move tapping this card would be
+// (0 = no move, 1 = Cell, 2 = normal, 3 = suit match, 4 = Foundation or Discard)
+func (c *Card) TapWeight() int {
+	return c.tapWeight
+}
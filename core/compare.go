@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 
+	"oddstream.games/gosol/cardid"
 	"oddstream.games/gosol/util"
 )
 
@@ -23,6 +24,9 @@ func TailConformant(tail []*Card, fn CardPairCompareFunc) (bool, error) {
 			return false, err
 		}
 	}
+	if unfilledJokerGaps(tail, fn) > 0 {
+		return false, errors.New("A joker can only stand for one card")
+	}
 	return true, nil
 }
 
@@ -44,7 +48,95 @@ func UnsortedPairs(pile *Pile, fn CardPairCompareFunc) int {
 			}
 		}
 	}
-	return unsorted
+	return unsorted + unfilledJokerGaps(pile.cards, fn)
+}
+
+// realCards are the cards a joker can stand for
+var realCards []*Card = func() []*Card {
+	var cards []*Card
+	for suit := cardid.CLUB; suit <= cardid.SPADE; suit++ {
+		for ord := 1; ord <= 13; ord++ {
+			var c Card = NewCard(0, suit, ord)
+			cards = append(cards, &c)
+		}
+	}
+	return cards
+}()
+
+// unfilledJokerGaps counts the runs of jokers between two face up cards that fn passes either side of,
+// as it does when it is Wild, but that no run of real cards could stand for, eg the joker in 6C JK 9H
+func unfilledJokerGaps(cards []*Card, fn CardPairCompareFunc) int {
+	var gaps int
+	for i := 0; i < len(cards); i++ {
+		if !cards[i].Joker() || i == 0 || cards[i-1].Joker() {
+			continue
+		}
+		var j int = i
+		for j < len(cards) && cards[j].Joker() {
+			j++
+		}
+		if j == len(cards) {
+			break
+		}
+		var before, after *Card = cards[i-1], cards[j]
+		if before.Prone() || after.Prone() {
+			continue
+		}
+		if ok, _ := fn(CardPair{before, cards[i]}); !ok {
+			continue
+		}
+		if ok, _ := fn(CardPair{cards[j-1], after}); !ok {
+			continue
+		}
+		if !jokersCanStandFor(fn, before, j-i, after) {
+			gaps++
+		}
+	}
+	return gaps
+}
+
+// jokersCanStandFor returns true if there are real cards for that many jokers to stand for,
+// that build by fn on before, and that after builds on
+func jokersCanStandFor(fn CardPairCompareFunc, before *Card, jokers int, after *Card) bool {
+	var could []*Card = []*Card{before}
+	for i := 0; i < jokers; i++ {
+		var next []*Card
+		for _, x := range realCards {
+			for _, c := range could {
+				if ok, _ := fn(CardPair{c, x}); ok {
+					next = append(next, x)
+					break
+				}
+			}
+		}
+		could = next
+	}
+	for _, c := range could {
+		if ok, _ := fn(CardPair{c, after}); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// appendConformant checks that a card can go on the top card of a pile by fn; if the pile ends in jokers,
+// the card must also build on what they stand for, from the card they build on
+func appendConformant(pile *Pile, c *Card, fn CardPairCompareFunc) (bool, error) {
+	if ok, err := fn(CardPair{pile.Peek(), c}); !ok {
+		return false, err
+	}
+	var i int = pile.Len() - 1
+	for i >= 0 && pile.cards[i].Joker() {
+		i--
+	}
+	if i < 0 || i == pile.Len()-1 {
+		return true, nil
+	}
+	var run []*Card = append(append([]*Card{}, pile.cards[i:]...), c)
+	if unfilledJokerGaps(run, fn) > 0 {
+		return false, errors.New("A joker can only stand for one card")
+	}
+	return true, nil
 }
 
 func NewCardPairs(cards []*Card) CardPairs {
@@ -72,6 +164,9 @@ func Compare_Empty(p *Pile, c *Card) (bool, error) {
 		if p.Label() == "x" || p.Label() == "X" {
 			return false, errors.New("Cannot move cards to that empty pile")
 		}
+		if c.Joker() {
+			return true, nil // it can be whatever the label asks for
+		}
 		ord := util.OrdinalToShortString(c.Ordinal())
 		if ord != p.Label() {
 			return false, fmt.Errorf("Can only accept %s, not %s", util.ShortOrdinalToLongOrdinal(p.Label()), util.ShortOrdinalToLongOrdinal(ord))
@@ -80,6 +175,20 @@ func Compare_Empty(p *Pile, c *Card) (bool, error) {
 	return true, nil
 }
 
+// Wild makes a compare that jokers always pass, so a variant that deals jokers can have them stand
+// for any card, eg Wild(CardPair.Compare_DownAltColor). A pair only shows one side of a joker, so TailConformant,
+// UnsortedPairs and appendConformant also check that the cards either side of it agree on what it stands for.
+// A joker can stand for a different card each time it is moved; that is why jokers are kept off the
+// Foundations, which must hold a true sequence.
+func Wild(fn CardPairCompareFunc) CardPairCompareFunc {
+	return func(cp CardPair) (bool, error) {
+		if cp.c1.Joker() || cp.c2.Joker() {
+			return true, nil
+		}
+		return fn(cp)
+	}
+}
+
 // little library of simple compares
 
 func (cp CardPair) Compare_Up() (bool, error) {
@@ -1,4 +1,4 @@
-package sol
+package core
 
 func (b *Baize) FindHomesForTail(tail []*Card) []*Pile {
 	var homes []*Pile
@@ -129,13 +129,6 @@ func (b *Baize) FindDestinations() {
 		}
 	}
 
-	b.UpdateToolbar()
-	b.UpdateDrawers()
-	b.UpdateStatusbar()
-
-	if !TheGame.Settings.AlwaysShowMovableCards {
-		TheGame.Settings.ShowMovableCards = false
-	}
 }
 
 /*
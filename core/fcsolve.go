@@ -0,0 +1,272 @@
+package core
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// Positions can be swapped with other solitaire programs, in the board format
+// read and written by Freecell Solver (fc-solve), eg
+//
+//	Foundations: H-2 C-0 D-A S-0
+//	Freecells: 7H - - -
+//	4C 2C 9C 8C QS 4S
+//	...
+//
+// with a line for each tableau pile, bottom card first, or in the format PySol uses
+// for the deals it gives to solvers, where each tableau line starts with a colon
+// and the cards in a face down stock are listed on a Talon line, next card first.
+// Neither format has face down tableau cards, more than one card in a foundation
+// slot, or reserves, so only some variants (Freecell, mostly) can be swapped.
+
+const (
+	FormatFCSolve = "fc-solve"
+	FormatPySol   = "pysol"
+)
+
+// solverRanks are the ranks of foundation tops in the Foundations line, 0 for none
+const solverRanks = "0A23456789TJQK"
+
+// solverSuits is the order suits are listed in the Foundations line
+var solverSuits = []int{cardid.HEART, cardid.CLUB, cardid.DIAMOND, cardid.SPADE}
+
+// ExportPosition writes a position in fc-solve or PySol format
+func ExportPosition(sav *SavableBaize, format string) (string, error) {
+	if format != FormatFCSolve && format != FormatPySol {
+		return "", fmt.Errorf("Unknown format %s", format)
+	}
+	var foundations []cardid.CardID // the top card of each foundation, 0 if it is empty
+	var cells, columns []string
+	var talon string
+	for _, sp := range sav.Piles {
+		switch sp.Category {
+		case "Foundation":
+			if len(sp.Cards) == 0 {
+				foundations = append(foundations, 0)
+				continue
+			}
+			var top cardid.CardID = sp.Cards[len(sp.Cards)-1]
+			for i, cid := range sp.Cards {
+				if cid.Prone() || cid.Suit() != top.Suit() || cid.Ordinal() != i+1 {
+					return "", errors.New("Foundations must be built up in suit from Ace")
+				}
+			}
+			foundations = append(foundations, top)
+		case "Cell":
+			if len(sp.Cards) == 0 {
+				cells = append(cells, "-")
+			} else {
+				cells = append(cells, solverCard(sp.Cards[0]))
+			}
+		case "Tableau":
+			var strs []string
+			for _, cid := range sp.Cards {
+				if cid.Prone() {
+					return "", errors.New("Tableau cards must all be face up")
+				}
+				strs = append(strs, solverCard(cid))
+			}
+			columns = append(columns, strings.Join(strs, " "))
+		case "Stock":
+			if len(sp.Cards) == 0 {
+				continue
+			}
+			if format != FormatPySol {
+				return "", errors.New("Stock must be empty")
+			}
+			var strs []string
+			for i := len(sp.Cards) - 1; i >= 0; i-- {
+				if !sp.Cards[i].Prone() {
+					return "", errors.New("Stock cards must all be face down")
+				}
+				strs = append(strs, solverCard(sp.Cards[i]))
+			}
+			talon = strings.Join(strs, " ")
+		default:
+			if len(sp.Cards) != 0 {
+				return "", fmt.Errorf("%s must be empty", sp.Category)
+			}
+		}
+	}
+	// foundations are listed in order, empty ones given the suits that have fewest,
+	// so a fresh deal lists each suit once, and the position can be read back as it was
+	var listed map[int]int = make(map[int]int)
+	for _, top := range foundations {
+		listed[top.Suit()]++
+	}
+	var founds []string
+	for _, top := range foundations {
+		if top != 0 {
+			founds = append(founds, fmt.Sprintf("%c-%c", solverSuitChar(top.Suit()), solverRanks[top.Ordinal()]))
+			continue
+		}
+		var suit int = solverSuits[0]
+		for _, s := range solverSuits {
+			if listed[s] < listed[suit] {
+				suit = s
+			}
+		}
+		listed[suit]++
+		founds = append(founds, fmt.Sprintf("%c-0", solverSuitChar(suit)))
+	}
+
+	var sb strings.Builder
+	if talon != "" {
+		sb.WriteString("Talon: " + talon + "\n")
+	}
+	if len(foundations) > 0 {
+		sb.WriteString("Foundations: " + strings.Join(founds, " ") + "\n")
+	}
+	if len(cells) > 0 {
+		sb.WriteString("Freecells: " + strings.Join(cells, " ") + "\n")
+	}
+	for _, col := range columns {
+		if format == FormatPySol {
+			col = strings.TrimSpace(": " + col)
+		} else if col == "" {
+			col = ":" // so the empty column is not taken for a blank line
+		}
+		sb.WriteString(col + "\n")
+	}
+	return sb.String(), nil
+}
+
+func solverSuitChar(suit int) byte {
+	return "?CDHS"[suit]
+}
+
+// solverCard writes a card, without its pack or which way up it is
+func solverCard(cid cardid.CardID) string {
+	return cardid.NewCardID(0, cid.Suit(), cid.Ordinal()).Notation()
+}
+
+// ImportPosition reads a position written in fc-solve or PySol format (either will do),
+// fitting it to the piles of a position of the variant it is for
+func ImportPosition(text string, template *SavableBaize) (*SavableBaize, error) {
+	// the cards the variant uses, to be taken as they are found
+	var unused []cardid.CardID
+	for _, sp := range template.Piles {
+		unused = append(unused, sp.Cards...)
+	}
+	var take = func(s string) (cardid.CardID, error) {
+		if strings.HasPrefix(s, "10") && len(s) == 3 {
+			s = "T" + s[2:] // fc-solve accepts 10 as well as T
+		}
+		cid, err := cardid.ParseNotation(strings.ToUpper(s))
+		if err != nil {
+			return 0, err
+		}
+		for i, u := range unused {
+			if u.Suit() == cid.Suit() && u.Ordinal() == cid.Ordinal() {
+				unused = append(unused[:i], unused[i+1:]...)
+				return u.SetProne(false), nil
+			}
+		}
+		return 0, fmt.Errorf("There are too many %s", s)
+	}
+
+	var sav *SavableBaize = copySavable(template)
+	sav.Seed, sav.Winnable = 0, false
+	var foundations, cells, tableaux, stocks []*SavablePile
+	for _, sp := range sav.Piles {
+		sp.Cards = nil
+		switch sp.Category {
+		case "Foundation":
+			foundations = append(foundations, sp)
+		case "Cell":
+			cells = append(cells, sp)
+		case "Tableau":
+			tableaux = append(tableaux, sp)
+		case "Stock":
+			stocks = append(stocks, sp)
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var fields []string = strings.Fields(line)
+		switch fields[0] {
+		case "Foundations:", "Founds:":
+			for _, f := range fields[1:] {
+				if len(f) != 3 || f[1] != '-' || strings.IndexByte("CDHS", f[0]) == -1 || strings.IndexByte(solverRanks, f[2]) == -1 {
+					return nil, fmt.Errorf("'%s' is not a foundation", f)
+				}
+				if len(foundations) == 0 {
+					return nil, errors.New("There are too many foundations")
+				}
+				var top int = strings.IndexByte(solverRanks, f[2])
+				for ord := 1; ord <= top; ord++ {
+					cid, err := take(string(solverRanks[ord]) + f[:1])
+					if err != nil {
+						return nil, err
+					}
+					foundations[0].Cards = append(foundations[0].Cards, cid)
+				}
+				foundations = foundations[1:]
+			}
+		case "Freecells:", "FC:":
+			var i int
+			for _, f := range fields[1:] {
+				if f == "-" {
+					i++
+					continue
+				}
+				if i >= len(cells) {
+					return nil, errors.New("There are too many freecells")
+				}
+				cid, err := take(f)
+				if err != nil {
+					return nil, err
+				}
+				cells[i].Cards = []cardid.CardID{cid}
+				i++
+			}
+		case "Talon:":
+			if len(stocks) == 0 {
+				return nil, errors.New("There is no stock")
+			}
+			for i := len(fields) - 1; i > 0; i-- {
+				cid, err := take(fields[i])
+				if err != nil {
+					return nil, err
+				}
+				stocks[0].Cards = append(stocks[0].Cards, cid.SetProne(true))
+			}
+		default:
+			if len(tableaux) == 0 {
+				return nil, errors.New("There are too many columns")
+			}
+			line = strings.TrimPrefix(line, ":")
+			for _, f := range strings.Fields(line) {
+				cid, err := take(f)
+				if err != nil {
+					return nil, err
+				}
+				tableaux[0].Cards = append(tableaux[0].Cards, cid)
+			}
+			tableaux = tableaux[1:]
+		}
+	}
+	if len(unused) > 0 {
+		return nil, fmt.Errorf("%d cards are missing, including %s", len(unused), solverCard(unused[0]))
+	}
+	return sav, nil
+}
+
+// ImportPosition starts a game from a position written in fc-solve or PySol format
+func (b *Baize) ImportPosition(text string) error {
+	sav, err := ImportPosition(text, b.Position())
+	if err != nil {
+		return err
+	}
+	b.Reset()
+	b.seed = 0
+	return b.SetUndoStack([]*SavableBaize{sav})
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"testing"
+)
+
+// msDeal1 is Microsoft FreeCell deal 1, as fc-solve writes it
+const msDeal1 = `Foundations: H-0 C-0 D-0 S-0
+Freecells: - - - -
+JD KD 2S 4C 3S 6D 6S
+2D KC KS 5C TD 8S 9C
+9H 9S 9D TS 4S 8D 2H
+JC 5S QD QH TH QS 6H
+5D AD JS 4H 8H 6C
+7H QC AS AC 2C 3D
+7C KH AH 4D JH 8C
+5H 3H 3C 7S 7D TC
+`
+
+// TestExportPosition checks positions can be written for other solvers and read back
+func TestExportPosition(t *testing.T) {
+	b := NewBaize("Freecell", nil)
+	b.StartFreshGame(NewSeed())
+	if err := b.NewMicrosoftDeal(1); err != nil {
+		t.Fatal(err)
+	}
+	text, err := ExportPosition(b.Position(), FormatFCSolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != msDeal1 {
+		t.Errorf("deal 1 written as\n%s", text)
+	}
+
+	for _, pos := range playRandomMoves(b, 40, 5) {
+		for _, format := range []string{FormatFCSolve, FormatPySol} {
+			text, err := ExportPosition(pos, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ImportPosition(text, pos)
+			if err != nil {
+				t.Fatal(format, err, "\n", text)
+			}
+			got.Seed, got.Shuffle = pos.Seed, pos.Shuffle
+			if FormatPosition(got) != FormatPosition(pos) {
+				t.Fatalf("%s\n%s\nread back as\n%s", format, text, FormatPosition(got))
+			}
+		}
+	}
+
+	// PySol can also list a stock
+	b = NewBaize("Blockade", nil)
+	b.StartFreshGame(NewSeed())
+	text, err = ExportPosition(b.Position(), FormatPySol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ImportPosition(text, b.Position())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Seed = b.Position().Seed
+	if FormatPosition(got) != FormatPosition(b.Position()) {
+		t.Errorf("%s\nread back as\n%s", text, FormatPosition(got))
+	}
+	if _, err := ExportPosition(b.Position(), FormatFCSolve); err == nil {
+		t.Error("fc-solve format has no stock")
+	}
+	b = NewBaize("Klondike", nil)
+	b.StartFreshGame(NewSeed())
+	if _, err := ExportPosition(b.Position(), FormatPySol); err == nil {
+		t.Error("exported face down tableau cards")
+	}
+}
+
+// TestImportPosition starts a game from a position written by another solver
+func TestImportPosition(t *testing.T) {
+	b := NewBaize("Freecell", nil)
+	b.StartFreshGame(NewSeed())
+	if err := b.ImportPosition(msDeal1); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.UndoStack()) != 1 || shortName(b.Script().Tableaux()[0].Peek().ID()) != "6S" {
+		t.Error("deal 1 not imported")
+	}
+	for _, text := range []string{
+		"Foundations: H-0 C-0 D-0 S-0\nJD",           // most of the cards are missing
+		msDeal1 + "JD\n",                             // too many cards, and columns
+		"Foundations: H-X\n",                         // not a card
+		"Freecells: 7H 7S 7D 7C 6H\n" + msDeal1[40:], // too many cells
+		"Talon: 2H\n" + msDeal1,                      // too many cards
+	} {
+		if err := b.ImportPosition(text); err == nil {
+			t.Errorf("imported\n%s", text)
+		}
+	}
+}
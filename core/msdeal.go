@@ -0,0 +1,72 @@
+package core
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"fmt"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// ShuffleType is the algorithm used to turn a deal number into a layout
+type ShuffleType int
+
+const (
+	SHUFFLE_GOSOL     ShuffleType = iota // math/rand shuffle driven by splitmix64 seeded with the deal number
+	SHUFFLE_MICROSOFT                    // the Microsoft FreeCell deal numbering
+)
+
+// MaxMicrosoftDeal is the largest deal number the Microsoft generator can make;
+// it covers both the original 1..32000 and the extended 1..1,000,000 deals
+const MaxMicrosoftDeal = 0x7FFFFFFF
+
+// microsoftDeal returns the 52 cards of a Microsoft FreeCell deal, in the order
+// they are dealt, left to right across eight columns, one row at a time.
+// see https://rosettacode.org/wiki/Deal_cards_for_FreeCell
+func microsoftDeal(number uint64) []cardid.CardID {
+	// deck[i] is the card of rank i/4 (Ace first), suit i%4 (clubs, diamonds, hearts, spades)
+	var deck [52]int
+	for i := range deck {
+		deck[i] = i
+	}
+	var state uint64 = number
+	var dealt []cardid.CardID = make([]cardid.CardID, 0, 52)
+	for left := 52; left > 0; left-- {
+		// the Microsoft C runtime rand()
+		state = (state*214013 + 2531011) & 0x7FFFFFFF
+		j := int(state>>16) % left
+		dealt = append(dealt, cardid.NewCardID(0, deck[j]%4+cardid.CLUB, deck[j]/4+1))
+		deck[j] = deck[left-1]
+	}
+	return dealt
+}
+
+// arrangeMicrosoftStock orders a 52 card stock so that dealing it column by column
+// into eight tableaux (seven cards to each of the first four, six to the rest)
+// gives the same layout as Microsoft FreeCell deal number
+func arrangeMicrosoftStock(stock *Pile, number uint64) error {
+	if stock.Len() != 52 {
+		return errors.New("Microsoft deals need a single pack of cards")
+	}
+	var cards map[cardid.CardID]*Card = make(map[cardid.CardID]*Card, 52)
+	for _, c := range stock.cards {
+		cards[c.id.PackSuitOrdinal()] = c
+	}
+	var dealt []cardid.CardID = microsoftDeal(number)
+	// the order the cards will be popped off the stock
+	var order []*Card = make([]*Card, 0, 52)
+	for col := 0; col < 8; col++ {
+		for i := col; i < 52; i += 8 {
+			c, ok := cards[dealt[i].PackSuitOrdinal()]
+			if !ok {
+				return fmt.Errorf("Cannot find %s in the stock", dealt[i])
+			}
+			order = append(order, c)
+		}
+	}
+	for i, c := range order {
+		stock.cards[len(order)-1-i] = c
+	}
+	return nil
+}
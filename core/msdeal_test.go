@@ -0,0 +1,51 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// shortName returns a card as written in the FreeCell literature, eg "JD"
+func shortName(cid cardid.CardID) string {
+	return string("A23456789TJQK"[cid.Ordinal()-1]) + string("CDHS"[cid.Suit()-1])
+}
+
+// TestMicrosoftDeal checks deal #1 against the well known layout
+func TestMicrosoftDeal(t *testing.T) {
+	var want []string = []string{
+		"JD KD 2S 4C 3S 6D 6S",
+		"2D KC KS 5C TD 8S 9C",
+		"9H 9S 9D TS 4S 8D 2H",
+		"JC 5S QD QH TH QS 6H",
+		"5D AD JS 4H 8H 6C",
+		"7H QC AS AC 2C 3D",
+		"7C KH AH 4D JH 8C",
+		"5H 3H 3C 7S 7D TC",
+	}
+	for _, name := range []string{"Freecell", "Baker's Game", "Blind Freecell"} {
+		b := NewBaize(name, nil)
+		b.StartFreshGame(NewSeed())
+		if err := b.NewMicrosoftDeal(1); err != nil {
+			t.Fatal(err)
+		}
+		for i, tab := range b.Script().Tableaux() {
+			var got []string
+			for _, c := range tab.Cards() {
+				got = append(got, shortName(c.ID()))
+			}
+			if strings.Join(got, " ") != want[i] {
+				t.Errorf("%s deal 1 column %d is %v, expected %s", name, i+1, got, want[i])
+			}
+		}
+		if b.UndoPeek().Shuffle != SHUFFLE_MICROSOFT {
+			t.Errorf("%s: shuffle type not saved", name)
+		}
+	}
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame(NewSeed())
+	if err := b.NewMicrosoftDeal(1); err == nil {
+		t.Error("Klondike should not have Microsoft deals")
+	}
+}
@@ -0,0 +1,526 @@
+package core
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"oddstream.games/gosol/cardid"
+	"oddstream.games/gosol/util"
+)
+
+// Positions and moves can be written as text, to be pasted into bug reports and tests.
+//
+// A position is a line for each pile, in the order the variant makes them, eg
+//
+//	Seed: 12345
+//	Recycles: 2
+//	S: kd 3c 9h
+//	W: 7H 2S
+//	F1[A]: AH 2H
+//	T1[K]: 5s 7D 6C
+//	T2[K]:
+//
+// Piles are named by their kind (S stock, W waste, C cell, F foundation, T tableau, R reserve, D discard),
+// numbered from 1 unless there is only one of that kind. A label follows in square brackets.
+// Cards are written as cardid.CardID.Notation, bottom card first, face down cards in lower case.
+// Seed, Shuffle (Microsoft), Recycles and Winnable lines are left out if they are zero.
+// Blank lines and lines starting with # are ignored.
+//
+// A move is one or more steps separated by commas, with no spaces, eg "3T1-T4,^T1":
+//
+//	T1-F2      the top card of T1 goes to F2; 3T1-T4 moves the top three cards, keeping their order
+//	5W~S       the top five cards of W go to S one at a time, so their order is reversed
+//	^T1, 3vW   the top card of T1 is turned face up, the top three cards of W face down
+//	T1=7H.6S   T1 now holds exactly these cards (for anything the other steps cannot describe)
+//	F1[7]      the label of F1 is now 7
+//	recycles=1 the number of recycles left is now 1
+//	.          nothing changed
+//
+// A game is a position, then a line saying Moves:, then the moves, separated by spaces or new lines.
+
+// pileLetters are the first letters of pile names, by category
+var pileLetters = map[string]string{
+	"Stock":      "S",
+	"Waste":      "W",
+	"Cell":       "C",
+	"Foundation": "F",
+	"Tableau":    "T",
+	"Reserve":    "R",
+	"Discard":    "D",
+}
+
+func pileCategory(letter string) string {
+	for category, l := range pileLetters {
+		if l == letter {
+			return category
+		}
+	}
+	return ""
+}
+
+// pileNames returns the names of the piles in text notation, in order
+func pileNames(piles []*SavablePile) []string {
+	var counts map[string]int = make(map[string]int)
+	for _, sp := range piles {
+		counts[sp.Category]++
+	}
+	var names []string = make([]string, 0, len(piles))
+	var seen map[string]int = make(map[string]int)
+	for _, sp := range piles {
+		var letter string = pileLetters[sp.Category]
+		if letter == "" {
+			letter = "?"
+		}
+		seen[sp.Category]++
+		if counts[sp.Category] == 1 {
+			names = append(names, letter)
+		} else {
+			names = append(names, letter+strconv.Itoa(seen[sp.Category]))
+		}
+	}
+	return names
+}
+
+// PileNames returns the names of the piles in text notation, in order, eg T1 for the first tableau
+func (b *Baize) PileNames() []string {
+	return pileNames(b.Position().Piles)
+}
+
+// pileIndex finds a pile by name; a pile that is the only one of its kind can also be named with a 1, eg S1
+func pileIndex(names []string, name string) (int, error) {
+	for i, n := range names {
+		if n == name || n+"1" == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("No pile called %s", name)
+}
+
+func formatCards(cards []cardid.CardID, sep string) string {
+	var strs []string = make([]string, 0, len(cards))
+	for _, cid := range cards {
+		strs = append(strs, cid.Notation())
+	}
+	return strings.Join(strs, sep)
+}
+
+func parseCards(s string, sep string) ([]cardid.CardID, error) {
+	var cards []cardid.CardID
+	for _, str := range strings.Split(s, sep) {
+		if str = strings.TrimSpace(str); str == "" {
+			continue
+		}
+		cid, err := cardid.ParseNotation(str)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, cid)
+	}
+	return cards, nil
+}
+
+// FormatPosition writes a position in text notation
+func FormatPosition(sav *SavableBaize) string {
+	var sb strings.Builder
+	if sav.Seed != 0 {
+		fmt.Fprintf(&sb, "Seed: %d\n", sav.Seed)
+	}
+	if sav.Shuffle == SHUFFLE_MICROSOFT {
+		sb.WriteString("Shuffle: Microsoft\n")
+	}
+	if sav.Recycles != 0 {
+		fmt.Fprintf(&sb, "Recycles: %d\n", sav.Recycles)
+	}
+	if sav.Winnable {
+		sb.WriteString("Winnable: true\n")
+	}
+	for i, name := range pileNames(sav.Piles) {
+		sb.WriteString(name)
+		if sav.Piles[i].Label != "" {
+			fmt.Fprintf(&sb, "[%s]", sav.Piles[i].Label)
+		}
+		sb.WriteString(":")
+		if len(sav.Piles[i].Cards) > 0 {
+			sb.WriteString(" " + formatCards(sav.Piles[i].Cards, " "))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// ParsePosition reads a position written in text notation
+func ParsePosition(text string) (*SavableBaize, error) {
+	var sav *SavableBaize = &SavableBaize{}
+	var counts map[string]int = make(map[string]int)
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var colon int = strings.Index(line, ":")
+		if open := strings.Index(line, "["); open != -1 && open < colon {
+			// the label may have a colon in it
+			if close := strings.Index(line, "]"); close > open {
+				colon = close + strings.Index(line[close:], ":")
+			}
+		}
+		if colon < 1 {
+			return nil, fmt.Errorf("'%s' is not a pile", line)
+		}
+		var key, value string = strings.TrimSpace(line[:colon]), strings.TrimSpace(line[colon+1:])
+		var err error
+		switch key {
+		case "Seed":
+			sav.Seed, err = strconv.ParseUint(value, 10, 64)
+		case "Shuffle":
+			if value == "Microsoft" {
+				sav.Shuffle = SHUFFLE_MICROSOFT
+			} else if value != "Gosol" {
+				err = fmt.Errorf("Unknown shuffle %s", value)
+			}
+		case "Recycles":
+			sav.Recycles, err = strconv.Atoi(value)
+		case "Winnable":
+			sav.Winnable, err = strconv.ParseBool(value)
+		default:
+			var sp *SavablePile
+			if sp, err = parsePile(key, value, counts); err == nil {
+				sav.Piles = append(sav.Piles, sp)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if len(sav.Piles) == 0 {
+		return nil, errors.New("Position has no piles")
+	}
+	return sav, nil
+}
+
+// parsePile reads one pile of a position, eg "T3[K]" and "kd 7H"; counts are the piles of each kind so far
+func parsePile(key, value string, counts map[string]int) (*SavablePile, error) {
+	var sp *SavablePile = &SavablePile{}
+	if open := strings.Index(key, "["); open != -1 {
+		if !strings.HasSuffix(key, "]") {
+			return nil, errors.New("Label is not closed")
+		}
+		sp.Label = key[open+1 : len(key)-1]
+		key = key[:open]
+	}
+	if key == "" {
+		return nil, errors.New("Unknown kind of pile")
+	}
+	if sp.Category = pileCategory(key[:1]); sp.Category == "" {
+		return nil, errors.New("Unknown kind of pile")
+	}
+	counts[sp.Category]++
+	if len(key) > 1 {
+		if n, err := strconv.Atoi(key[1:]); err != nil || n != counts[sp.Category] {
+			return nil, errors.New("Pile is out of order")
+		}
+	}
+	var err error
+	sp.Cards, err = parseCards(value, " ")
+	return sp, err
+}
+
+// copySavable copies a position, so the copy can be changed without changing the original
+func copySavable(sav *SavableBaize) *SavableBaize {
+	var cp SavableBaize = *sav
+	cp.Piles = make([]*SavablePile, 0, len(sav.Piles))
+	for _, sp := range sav.Piles {
+		var p SavablePile = *sp
+		p.Cards = append([]cardid.CardID(nil), sp.Cards...)
+		cp.Piles = append(cp.Piles, &p)
+	}
+	return &cp
+}
+
+// sameCards returns true if the two lists hold the same cards in the same order, face up or down
+func sameCards(a, b []cardid.CardID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].PackSuitOrdinal() != b[i].PackSuitOrdinal() {
+			return false
+		}
+	}
+	return true
+}
+
+// countPrefix returns how many of the bottom cards of a and b are the same, face up or down
+func countPrefix(a, b []cardid.CardID) int {
+	var n int
+	for n < len(a) && n < len(b) && a[n].PackSuitOrdinal() == b[n].PackSuitOrdinal() {
+		n++
+	}
+	return n
+}
+
+func reversed(cards []cardid.CardID) []cardid.CardID {
+	var rev []cardid.CardID = make([]cardid.CardID, 0, len(cards))
+	for i := len(cards) - 1; i >= 0; i-- {
+		rev = append(rev, cards[i])
+	}
+	return rev
+}
+
+// countStep is the number in front of a step, left out if it is 1
+func countStep(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// FormatMove writes the change from one position to the next in text notation
+func FormatMove(prev, next *SavableBaize) (string, error) {
+	if len(prev.Piles) != len(next.Piles) {
+		return "", errors.New("Positions have different piles")
+	}
+	var names []string = pileNames(prev.Piles)
+	var work *SavableBaize = copySavable(prev)
+	var steps []string
+
+	// move the cards a pile needs from the tops of piles that have cards to give,
+	// until there is nothing more that can be done that way
+	for progress := true; progress; {
+		progress = false
+		for d, dp := range work.Piles {
+			var want []cardid.CardID = next.Piles[d].Cards
+			if len(dp.Cards) >= len(want) || countPrefix(dp.Cards, want) != len(dp.Cards) {
+				continue
+			}
+			want = want[len(dp.Cards):]
+			var bestSrc, bestN int = -1, 0
+			var bestReversed bool
+			for s, sp := range work.Piles {
+				var spare int = len(sp.Cards) - countPrefix(sp.Cards, next.Piles[s].Cards)
+				if s == d || spare == 0 {
+					continue
+				}
+				var top []cardid.CardID = sp.Cards[len(sp.Cards)-spare:]
+				for n := util.Min(spare, len(want)); n > bestN; n-- {
+					if sameCards(top[len(top)-n:], want[:n]) {
+						bestSrc, bestN, bestReversed = s, n, false
+						break
+					}
+				}
+				for n := util.Min(spare, len(want)); n > bestN && n > 1; n-- {
+					if sameCards(reversed(top[len(top)-n:]), want[:n]) {
+						bestSrc, bestN, bestReversed = s, n, true
+						break
+					}
+				}
+			}
+			if bestSrc == -1 {
+				continue
+			}
+			var sp *SavablePile = work.Piles[bestSrc]
+			var moving []cardid.CardID = sp.Cards[len(sp.Cards)-bestN:]
+			var arrow string = "-"
+			if bestReversed {
+				moving, arrow = reversed(moving), "~"
+			}
+			dp.Cards = append(dp.Cards, moving...)
+			sp.Cards = sp.Cards[: len(sp.Cards)-bestN : len(sp.Cards)-bestN]
+			steps = append(steps, countStep(bestN)+names[bestSrc]+arrow+names[d])
+			progress = true
+		}
+	}
+
+	for i, wp := range work.Piles {
+		var want []cardid.CardID = next.Piles[i].Cards
+		if sameCards(wp.Cards, want) {
+			// turn the top cards over
+			var first int = len(want)
+			for j := range want {
+				if wp.Cards[j] != want[j] {
+					first = j
+					break
+				}
+			}
+			if first < len(want) {
+				var prone bool = want[first].Prone()
+				var ok bool = true
+				for j := first; j < len(want); j++ {
+					ok = ok && (want[j].Prone() == prone || wp.Cards[j] == want[j])
+				}
+				if ok {
+					var flip string = "^"
+					if prone {
+						flip = "v"
+					}
+					for j := first; j < len(want); j++ {
+						wp.Cards[j] = wp.Cards[j].SetProne(prone)
+					}
+					steps = append(steps, countStep(len(want)-first)+flip+names[i])
+				}
+			}
+		}
+		if len(wp.Cards) != len(want) || countPrefix(wp.Cards, want) != len(want) || !sameFaces(wp.Cards, want) {
+			wp.Cards = append([]cardid.CardID(nil), want...)
+			steps = append(steps, names[i]+"="+formatCards(want, "."))
+		}
+	}
+	for i, wp := range work.Piles {
+		if wp.Label != next.Piles[i].Label {
+			steps = append(steps, names[i]+"["+next.Piles[i].Label+"]")
+		}
+	}
+	if prev.Recycles != next.Recycles {
+		steps = append(steps, fmt.Sprintf("recycles=%d", next.Recycles))
+	}
+	if len(steps) == 0 {
+		return ".", nil
+	}
+	return strings.Join(steps, ","), nil
+}
+
+func sameFaces(a, b []cardid.CardID) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyMove makes a move written in text notation, returning the position after it
+func ApplyMove(sav *SavableBaize, move string) (*SavableBaize, error) {
+	var names []string = pileNames(sav.Piles)
+	var next *SavableBaize = copySavable(sav)
+	if move == "." {
+		return next, nil
+	}
+	for _, step := range strings.Split(move, ",") {
+		if err := applyStep(next, names, step); err != nil {
+			return nil, fmt.Errorf("%s: %w", step, err)
+		}
+	}
+	return next, nil
+}
+
+func applyStep(sav *SavableBaize, names []string, step string) error {
+	if strings.HasPrefix(step, "recycles=") {
+		n, err := strconv.Atoi(strings.TrimPrefix(step, "recycles="))
+		sav.Recycles = n
+		return err
+	}
+	if eq := strings.Index(step, "="); eq != -1 {
+		i, err := pileIndex(names, step[:eq])
+		if err != nil {
+			return err
+		}
+		sav.Piles[i].Cards, err = parseCards(step[eq+1:], ".")
+		return err
+	}
+	if open := strings.Index(step, "["); open != -1 {
+		i, err := pileIndex(names, step[:open])
+		if err != nil {
+			return err
+		}
+		if !strings.HasSuffix(step, "]") {
+			return errors.New("Label is not closed")
+		}
+		sav.Piles[i].Label = step[open+1 : len(step)-1]
+		return nil
+	}
+
+	// the rest start with an optional count
+	var digits int
+	for digits < len(step) && step[digits] >= '0' && step[digits] <= '9' {
+		digits++
+	}
+	var n int = 1
+	if digits > 0 {
+		n, _ = strconv.Atoi(step[:digits])
+	}
+	step = step[digits:]
+	if strings.HasPrefix(step, "^") || strings.HasPrefix(step, "v") {
+		i, err := pileIndex(names, step[1:])
+		if err != nil {
+			return err
+		}
+		var cards []cardid.CardID = sav.Piles[i].Cards
+		if n < 1 || n > len(cards) {
+			return errors.New("Not enough cards to turn over")
+		}
+		for j := len(cards) - n; j < len(cards); j++ {
+			cards[j] = cards[j].SetProne(step[0] == 'v')
+		}
+		return nil
+	}
+	var arrow int = strings.IndexAny(step, "-~")
+	if arrow == -1 {
+		return errors.New("Unknown step")
+	}
+	src, err := pileIndex(names, step[:arrow])
+	if err != nil {
+		return err
+	}
+	dst, err := pileIndex(names, step[arrow+1:])
+	if err != nil {
+		return err
+	}
+	var from *SavablePile = sav.Piles[src]
+	if n < 1 || n > len(from.Cards) || src == dst {
+		return errors.New("Not enough cards to move")
+	}
+	var moving []cardid.CardID = from.Cards[len(from.Cards)-n:]
+	if step[arrow] == '~' {
+		moving = reversed(moving)
+	}
+	sav.Piles[dst].Cards = append(sav.Piles[dst].Cards, moving...)
+	from.Cards = from.Cards[: len(from.Cards)-n : len(from.Cards)-n]
+	return nil
+}
+
+// FormatGame writes a position and the moves after it in text notation, one move to a line
+func FormatGame(positions []*SavableBaize) (string, error) {
+	if len(positions) == 0 {
+		return "", errors.New("No positions")
+	}
+	var sb strings.Builder
+	sb.WriteString(FormatPosition(positions[0]))
+	sb.WriteString("Moves:\n")
+	for i := 1; i < len(positions); i++ {
+		move, err := FormatMove(positions[i-1], positions[i])
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(move + "\n")
+	}
+	return sb.String(), nil
+}
+
+// ParseGame reads a position and the moves after it written in text notation,
+// and returns the position after each move, the first position first
+func ParseGame(text string) ([]*SavableBaize, error) {
+	var moves string
+	if i := strings.Index(text, "Moves:"); i != -1 {
+		text, moves = text[:i], text[i+len("Moves:"):]
+	}
+	sav, err := ParsePosition(text)
+	if err != nil {
+		return nil, err
+	}
+	var positions []*SavableBaize = []*SavableBaize{sav}
+	for _, line := range strings.Split(moves, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, move := range strings.Fields(line) {
+			if sav, err = ApplyMove(sav, move); err != nil {
+				return nil, fmt.Errorf("Move %d %w", len(positions), err)
+			}
+			positions = append(positions, sav)
+		}
+	}
+	return positions, nil
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// TestCardNotation checks every card of two packs can be written and read back
+func TestCardNotation(t *testing.T) {
+	for pack := 0; pack < 2; pack++ {
+		for suit := cardid.NOSUIT; suit <= cardid.SPADE; suit++ {
+			for ord := 0; ord <= 13; ord++ {
+				if (suit == cardid.NOSUIT) != (ord == 0) {
+					continue
+				}
+				for _, prone := range []bool{false, true} {
+					var cid cardid.CardID = cardid.NewCardID(pack, suit, ord).SetProne(prone)
+					got, err := cardid.ParseNotation(cid.Notation())
+					if err != nil || got != cid {
+						t.Errorf("%s read back as %s, %v", cid.Notation(), got.Notation(), err)
+					}
+				}
+			}
+		}
+	}
+	for _, s := range []string{"", "7", "1H", "7X", "7H+1", "7Hx", "Jk"} {
+		if _, err := cardid.ParseNotation(s); err == nil {
+			t.Errorf("'%s' read as a card", s)
+		}
+	}
+}
+
+// TestGameNotation plays some games, and checks the moves written as text take the deal to the same positions
+func TestGameNotation(t *testing.T) {
+	for _, name := range []string{"Klondike", "Spider Two Suits", "Freecell", "Canfield", "Forty Thieves"} {
+		b := NewBaize(name, nil)
+		b.StartFreshGame(54321)
+		positions := playRandomMoves(b, 60, 3)
+		text, err := FormatGame(positions)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		got, err := ParseGame(text)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if len(got) != len(positions) {
+			t.Fatalf("%s: read %d positions, wanted %d", name, len(got), len(positions))
+		}
+		for i := range positions {
+			if FormatPosition(got[i]) != FormatPosition(positions[i]) {
+				t.Fatalf("%s: position %d is\n%s\nwanted\n%s", name, i, FormatPosition(got[i]), FormatPosition(positions[i]))
+			}
+		}
+		// the moves should mostly be described by moving and turning cards
+		if n := strings.Count(text, "="); n > len(positions)/4 {
+			t.Errorf("%s: %d moves set a pile outright\n%s", name, n, text)
+		}
+	}
+}
+
+// TestPositionNotation reads a position written by hand
+func TestPositionNotation(t *testing.T) {
+	sav, err := ParsePosition(`
+# a bug report
+Seed: 7
+Recycles: 1
+S: kd qd
+W:
+F1[A]: AH 2H
+F2[A]:
+T1[K]: 5s 7D 6C
+T2: JK1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sav.Seed != 7 || sav.Recycles != 1 || len(sav.Piles) != 6 || sav.Piles[2].Label != "A" || len(sav.Piles[4].Cards) != 3 || !sav.Piles[4].Cards[0].Prone() {
+		t.Errorf("read as\n%s", FormatPosition(sav))
+	}
+	next, err := ApplyMove(sav, "T1-F3,^T1")
+	if err == nil {
+		t.Error("moved to a pile that is not there")
+	}
+	next, err = ApplyMove(sav, "2T1-T2,^T1,F2[7]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move, _ := FormatMove(sav, next); move != "2T1-T2,^T1,F2[7]" {
+		t.Errorf("move written as %s", move)
+	}
+	next, err = ApplyMove(sav, "2S~W,2^W,recycles=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move, _ := FormatMove(sav, next); move != "2S~W,2^W,recycles=0" {
+		t.Errorf("move written as %s", move)
+	}
+
+	for _, text := range []string{"", "S: 7X", "T2: 7H\nT1: 7S", "Q: 7H", "[A]: AH", "Seed: x\nS:"} {
+		if _, err := ParsePosition(text); err == nil {
+			t.Errorf("'%s' read as a position", text)
+		}
+	}
+	for _, move := range []string{"4T1-T2", "T1-T1", "T3-T1", "5^W", "S=7X", "F1[7", "T1+T2"} {
+		if _, err := ApplyMove(sav, move); err == nil {
+			t.Errorf("'%s' read as a move", move)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 Receiver name will be anything I like, thank you
@@ -6,18 +6,14 @@ package sol
 import (
 	"errors"
 	"image"
-	"image/color"
-
-	"github.com/fogleman/gg"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type Cell struct {
 	pile *Pile
 }
 
-func NewCell(slot image.Point) *Pile {
-	pile := NewPile("Cell", slot, FAN_NONE, MOVE_ONE)
+func NewCell(baize *Baize, slot image.Point) *Pile {
+	pile := NewPile(baize, "Cell", slot, FAN_NONE, MOVE_ONE)
 	pile.vtable = &Cell{pile: pile}
 	return pile
 }
@@ -53,21 +49,10 @@ func (self *Cell) MovableTails() []*MovableTail {
 	if self.pile.Len() > 0 {
 		var card *Card = self.pile.Peek()
 		var tail []*Card = []*Card{card}
-		var homes []*Pile = TheGame.Baize.FindHomesForTail(tail)
+		var homes []*Pile = self.pile.baize.FindHomesForTail(tail)
 		for _, home := range homes {
 			tails = append(tails, &MovableTail{dst: home, tail: tail})
 		}
 	}
 	return tails
 }
-
-// Placeholder creates a basic outline
-func (self *Cell) Placeholder() *ebiten.Image {
-	dc := gg.NewContext(CardWidth, CardHeight)
-	dc.SetColor(color.NRGBA{255, 255, 255, 31})
-	dc.SetLineWidth(2)
-	// draw the RoundedRect entirely INSIDE the context
-	dc.DrawRoundedRectangle(1, 1, float64(CardWidth-2), float64(CardHeight-2), CardCornerRadius)
-	dc.Stroke()
-	return ebiten.NewImageFromImage(dc.Image())
-}
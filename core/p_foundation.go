@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 Receiver name will be anything I like, thank you
@@ -6,19 +6,14 @@ package sol
 import (
 	"errors"
 	"image"
-	"image/color"
-
-	"github.com/fogleman/gg"
-	"github.com/hajimehoshi/ebiten/v2"
-	"oddstream.games/gosol/schriftbank"
 )
 
 type Foundation struct {
 	pile *Pile
 }
 
-func NewFoundation(slot image.Point) *Pile {
-	pile := NewPile("Foundation", slot, FAN_NONE, MOVE_NONE)
+func NewFoundation(baize *Baize, slot image.Point) *Pile {
+	pile := NewPile(baize, "Foundation", slot, FAN_NONE, MOVE_NONE)
 	pile.vtable = &Foundation{pile: pile}
 	return pile
 }
@@ -34,7 +29,10 @@ func (self *Foundation) CanAcceptTail(tail []*Card) (bool, error) {
 	if AnyCardsProne(tail) {
 		return false, errors.New("Cannot add a face down card to a Foundation")
 	}
-	return TheGame.Baize.script.TailAppendError(self.pile, tail)
+	if tail[0].Joker() {
+		return false, errors.New("Cannot add a joker to a Foundation")
+	}
+	return self.pile.baize.script.TailAppendError(self.pile, tail)
 }
 
 func (*Foundation) TailTapped([]*Card) {}
@@ -51,17 +49,3 @@ func (*Foundation) UnsortedPairs() int {
 func (*Foundation) MovableTails() []*MovableTail {
 	return nil
 }
-
-func (self *Foundation) Placeholder() *ebiten.Image {
-	dc := gg.NewContext(CardWidth, CardHeight)
-	dc.SetColor(color.NRGBA{255, 255, 255, 31})
-	dc.SetLineWidth(2)
-	// draw the RoundedRect entirely INSIDE the context
-	dc.DrawRoundedRectangle(1, 1, float64(CardWidth-2), float64(CardHeight-2), CardCornerRadius)
-	if self.pile.label != "" {
-		dc.SetFontFace(schriftbank.CardOrdinalLarge)
-		dc.DrawStringAnchored(self.pile.label, float64(CardWidth)*0.5, float64(CardHeight)*0.4, 0.5, 0.5)
-	}
-	dc.Stroke()
-	return ebiten.NewImageFromImage(dc.Image())
-}
@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 Receiver name will be anything I like, thank you
@@ -6,17 +6,6 @@ package sol
 import (
 	"errors"
 	"image"
-	"image/color"
-
-	"github.com/fogleman/gg"
-	"github.com/hajimehoshi/ebiten/v2"
-	"oddstream.games/gosol/schriftbank"
-)
-
-const (
-	// https://en.wikipedia.org/wiki/Miscellaneous_Symbols
-	RECYCLE_RUNE   = rune(0x267B)
-	NORECYCLE_RUNE = rune(0x2613)
 )
 
 /***** ARCHIVED TO REMEMBER HOW TO DO suitFilter *******************
@@ -64,11 +53,11 @@ type Stock struct {
 	pile *Pile
 }
 
-func NewStock(slot image.Point, fanType FanType, packs int, suits int, cardFilter *[14]bool, jokersPerPack int) *Pile {
-	pile := NewPile("Stock", slot, fanType, MOVE_ONE)
+func NewStock(baize *Baize, slot image.Point, fanType FanType, packs int, suits int, cardFilter *[14]bool, jokersPerPack int) *Pile {
+	pile := NewPile(baize, "Stock", slot, fanType, MOVE_ONE)
 	pile.vtable = &Stock{pile: pile}
-	TheGame.Baize.cardCount = pile.Fill(packs, suits)
-	pile.Shuffle()
+	baize.cardCount = pile.Fill(packs, suits, jokersPerPack)
+	pile.Shuffle(baize.seed)
 	return pile
 }
 
@@ -97,34 +86,10 @@ func (self *Stock) MovableTails() []*MovableTail {
 	if self.pile.Len() > 0 {
 		var card *Card = self.pile.Peek()
 		var tail []*Card = []*Card{card}
-		var homes []*Pile = TheGame.Baize.FindHomesForTail(tail)
+		var homes []*Pile = self.pile.baize.FindHomesForTail(tail)
 		for _, home := range homes {
 			tails = append(tails, &MovableTail{dst: home, tail: tail})
 		}
 	}
 	return tails
 }
-
-func (*Stock) Placeholder() *ebiten.Image {
-	dc := gg.NewContext(CardWidth, CardHeight)
-	dc.SetColor(color.NRGBA{255, 255, 255, 31})
-	dc.SetLineWidth(2)
-	// draw the RoundedRect entirely INSIDE the context
-	dc.DrawRoundedRectangle(1, 1, float64(CardWidth-2), float64(CardHeight-2), CardCornerRadius)
-
-	// farted around trying to use icons for this
-	// but they were 48x48 and got fuzzy when scaled
-	// and were stubbornly white
-
-	var label rune
-	if TheGame.Baize.recycles == 0 {
-		label = NORECYCLE_RUNE
-	} else {
-		label = RECYCLE_RUNE
-	}
-	dc.SetFontFace(schriftbank.CardSymbolHuge)
-	dc.DrawStringAnchored(string(label), float64(CardWidth)*0.5, float64(CardHeight)*0.4, 0.5, 0.5)
-
-	dc.Stroke()
-	return ebiten.NewImageFromImage(dc.Image())
-}
@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 Receiver name will be anything I like, thank you
@@ -6,16 +6,14 @@ package sol
 import (
 	"errors"
 	"image"
-
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type Waste struct {
 	pile *Pile
 }
 
-func NewWaste(slot image.Point, fanType FanType) *Pile {
-	pile := NewPile("Waste", slot, fanType, MOVE_ONE)
+func NewWaste(baize *Baize, slot image.Point, fanType FanType) *Pile {
+	pile := NewPile(baize, "Waste", slot, fanType, MOVE_ONE)
 	pile.vtable = &Waste{pile: pile}
 	return pile
 }
@@ -54,14 +52,10 @@ func (self *Waste) MovableTails() []*MovableTail {
 	if self.pile.Len() > 0 {
 		var card *Card = self.pile.Peek()
 		var tail []*Card = []*Card{card}
-		var homes []*Pile = TheGame.Baize.FindHomesForTail(tail)
+		var homes []*Pile = self.pile.baize.FindHomesForTail(tail)
 		for _, home := range homes {
 			tails = append(tails, &MovableTail{dst: home, tail: tail})
 		}
 	}
 	return tails
 }
-
-func (*Waste) Placeholder() *ebiten.Image {
-	return nil
-}
@@ -0,0 +1,387 @@
+package core
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"math/rand"
+
+	"oddstream.games/gosol/cardid"
+)
+
+type FanType int
+
+const (
+	FAN_NONE FanType = iota
+	FAN_DOWN
+	FAN_LEFT
+	FAN_RIGHT
+	FAN_DOWN3
+	FAN_LEFT3
+	FAN_RIGHT3
+)
+
+type MoveType int
+
+const (
+	MOVE_NONE MoveType = iota
+	MOVE_ANY
+	MOVE_ONE
+	MOVE_ONE_PLUS
+	MOVE_ONE_OR_ALL
+)
+
+// MovableTail is used for collecting tap destinations
+type MovableTail struct {
+	dst  *Pile
+	tail []*Card
+}
+
+// Dst returns the pile the tail can be moved to
+func (mt *MovableTail) Dst() *Pile {
+	return mt.dst
+}
+
+// Tail returns the cards that can be moved
+func (mt *MovableTail) Tail() []*Card {
+	return mt.tail
+}
+
+// PileVtabler interface for each subpile type, implements the behaviours
+// specific to each subtype
+type PileVtabler interface {
+	CanAcceptTail([]*Card) (bool, error)
+	TailTapped([]*Card)
+	Conformant() bool
+	UnsortedPairs() int
+	MovableTails() []*MovableTail
+}
+
+// Pile is a generic container for cards
+type Pile struct {
+	baize    *Baize
+	category string
+	vtable   PileVtabler
+	label    string
+	moveType MoveType
+	fanType  FanType
+	cards    []*Card
+	slot     image.Point // logical position on baize
+}
+
+func NewPile(baize *Baize, category string, slot image.Point, fanType FanType, moveType MoveType) *Pile {
+	var p *Pile = &Pile{
+		baize:    baize,
+		category: category,
+		moveType: moveType,
+		slot:     slot,
+		fanType:  fanType,
+	}
+	baize.AddPile(p)
+	return p
+}
+
+func (self *Pile) Reset() {
+	self.cards = self.cards[:0]
+}
+
+// Baize returns the Baize this pile belongs to
+func (self *Pile) Baize() *Baize {
+	return self.baize
+}
+
+// Vtable returns the subtype (*Stock, *Tableau &c) of this pile
+func (self *Pile) Vtable() PileVtabler {
+	return self.vtable
+}
+
+// Category returns the name of the subtype of this pile, eg "Tableau"
+func (self *Pile) Category() string {
+	return self.category
+}
+
+// Hidden returns true if this pile is off screen
+func (self *Pile) Hidden() bool {
+	return self.slot.X < 0 || self.slot.Y < 0
+}
+
+func (self *Pile) IsStock() bool {
+	// using a type assertion seems more idiomatic than a string comparison
+	_, ok := self.vtable.(*Stock)
+	return ok
+}
+
+// Fill this pile with a new set of cards, and jokers if the variant uses them. Returns the number of cards added.
+func (self *Pile) Fill(packs, suits, jokersPerPack int) int {
+	var count int = packs * (suits*13 + jokersPerPack)
+
+	self.cards = make([]*Card, 0, count)
+
+	for pack := 0; pack < packs; pack++ {
+		for suit := 0; suit < suits; suit++ {
+			for ord := 1; ord < 14; ord++ {
+				// suits are numbered NOSUIT=0, CLUB=1, DIAMOND=2, HEART=3, SPADE=4
+				// (i.e. not 0..3)
+				// run the suits loop backwards, so spades are used first
+				// (folks expect Spider One Suit to use spades)
+				var c Card = NewCard(pack, cardid.SPADE-suit, ord)
+				self.Push(&c)
+			}
+		}
+		for i := 0; i < jokersPerPack; i++ {
+			var c Card = NewCard(pack, cardid.NOSUIT, 0) // NOSUIT and ordinal == 0 creates a joker
+			self.Push(&c)
+		}
+	}
+
+	return count
+}
+
+// Shuffle the cards in this pile; the same seed will always give the same order
+func (self *Pile) Shuffle(seed uint64) {
+	rand.New(newSplitMix(seed)).Shuffle(self.Len(), self.Swap)
+	log.Printf("Shuffled %d cards with seed %d", self.Len(), seed)
+}
+
+func (self *Pile) Cards() []*Card {
+	return self.cards
+}
+
+func (self *Pile) FanType() FanType {
+	return self.fanType
+}
+
+func (self *Pile) SetFanType(fanType FanType) {
+	self.fanType = fanType
+}
+
+func (self *Pile) MoveType() MoveType {
+	return self.moveType
+}
+
+func (self *Pile) Label() string {
+	return self.label
+}
+
+func (self *Pile) SetLabel(label string) {
+	self.label = label
+}
+
+// Empty returns true if this pile is empty (has no cards).
+func (self *Pile) Empty() bool {
+	return len(self.cards) == 0
+}
+
+// Len returns the number of cards in this pile.
+// Len satisfies the sort.Interface interface.
+func (self *Pile) Len() int {
+	return len(self.cards)
+}
+
+// Less satisfies the sort.Interface interface
+func (self *Pile) Less(i, j int) bool {
+	c1 := self.cards[i]
+	c2 := self.cards[j]
+	return c1.Suit() < c2.Suit() && c1.Ordinal() < c2.Ordinal()
+}
+
+// Swap satisfies the sort.Interface interface
+func (self *Pile) Swap(i, j int) {
+	self.cards[i], self.cards[j] = self.cards[j], self.cards[i]
+}
+
+// Get a *Card from this pile
+func (self *Pile) Get(i int) *Card {
+	return self.cards[i]
+}
+
+// Append a *Card to this pile
+func (self *Pile) Append(c *Card) {
+	self.cards = append(self.cards, c)
+}
+
+// Delete a *Card from this pile
+func (self *Pile) Delete(index int) {
+	self.cards = append(self.cards[:index], self.cards[index+1:]...)
+}
+
+// Extract a specific *Card from this pile
+func (self *Pile) Extract(pack, ordinal, suit int) *Card {
+	var ID cardid.CardID = cardid.NewCardID(pack, suit, ordinal)
+	for i, c := range self.cards {
+		if cardid.SameCardAndPack(ID, c.id) {
+			self.Delete(i)
+			c.FlipUp()
+			return c
+		}
+	}
+	log.Printf("Could not find card %d %d in %s", suit, ordinal, self.category)
+	return nil
+}
+
+// Peek topmost Card of this Pile (a stack)
+func (self *Pile) Peek() *Card {
+	if len(self.cards) == 0 {
+		return nil
+	}
+	return self.cards[len(self.cards)-1]
+}
+
+// Pop a Card off the end of this Pile (a stack)
+func (self *Pile) Pop() *Card {
+	if len(self.cards) == 0 {
+		return nil
+	}
+	c := self.cards[len(self.cards)-1]
+	self.cards = self.cards[:len(self.cards)-1]
+	c.SetOwner(nil)
+	c.FlipUp()
+	return c
+}
+
+// Push a Card onto the end of this Pile (a stack)
+func (self *Pile) Push(c *Card) {
+	self.cards = append(self.cards, c)
+	c.SetOwner(self)
+	if self.IsStock() {
+		c.FlipDown()
+	}
+}
+
+func (self *Pile) FlipUpExposedCard() {
+	if !self.IsStock() {
+		if c := self.Peek(); c != nil {
+			c.FlipUp()
+		}
+	}
+}
+
+func (self *Pile) ReverseCards() {
+	for i, j := 0, len(self.cards)-1; i < j; i, j = i+1, j-1 {
+		self.cards[i], self.cards[j] = self.cards[j], self.cards[i]
+	}
+}
+
+// BuryCards moves cards with the specified ordinal to the beginning of the pile
+func (self *Pile) BuryCards(ordinal int) {
+	tmp := make([]*Card, 0, cap(self.cards))
+	for _, c := range self.cards {
+		if c.Ordinal() == ordinal {
+			tmp = append(tmp, c)
+		}
+	}
+	for _, c := range self.cards {
+		if c.Ordinal() != ordinal {
+			tmp = append(tmp, c)
+		}
+	}
+	self.Reset()
+	for i := 0; i < len(tmp); i++ {
+		self.Push(tmp[i])
+	}
+	// nb the card owner does not change
+}
+
+// Slot returns the virtual slot this pile is positioned at
+// TODO to use fractional slots, scale the slot values up by, say, 10.
+func (self *Pile) Slot() image.Point {
+	return self.slot
+}
+
+func (self *Pile) SetSlot(slot image.Point) {
+	self.slot = slot
+}
+
+// CanMoveTail filters out cases where a tail can be moved from a given pile type
+// eg if only one card can be moved at a time
+func (self *Pile) CanMoveTail(tail []*Card) (bool, error) {
+	if !self.IsStock() {
+		if AnyCardsProne(tail) {
+			return false, errors.New("Cannot move a face down card")
+		}
+	}
+	switch self.moveType {
+	case MOVE_NONE:
+		// eg Discard, Foundation
+		return false, fmt.Errorf("Cannot move a card from a %s", self.category)
+	case MOVE_ANY:
+		// well, that was easy
+	case MOVE_ONE:
+		// eg Cell, Reserve, Stock, Waste
+		if len(tail) > 1 {
+			return false, fmt.Errorf("Can only move one card from a %s", self.category)
+		}
+	case MOVE_ONE_PLUS:
+		// don't (yet) know destination, so we allow this as MOVE_ANY
+		// and do power moves check later, in Tableau CanAcceptTail
+	case MOVE_ONE_OR_ALL:
+		// Canfield, Toad
+		if len(tail) == 1 {
+			// that's okay
+		} else if len(tail) == self.Len() {
+			// that's okay too
+		} else {
+			return false, errors.New("Can only move one card, or the whole pile")
+		}
+	}
+	return true, nil
+}
+
+// CanAcceptTail asks the subtype of this pile if it will accept tail
+func (self *Pile) CanAcceptTail(tail []*Card) (bool, error) {
+	return self.vtable.CanAcceptTail(tail)
+}
+
+// Conformant asks the subtype of this pile if it is conformant
+func (self *Pile) Conformant() bool {
+	return self.vtable.Conformant()
+}
+
+// UnsortedPairs asks the subtype of this pile how many of its card pairs are unsorted
+func (self *Pile) UnsortedPairs() int {
+	return self.vtable.UnsortedPairs()
+}
+
+func (self *Pile) MakeTail(c *Card) []*Card {
+	if c.Owner() != self {
+		log.Panic("Pile.MakeTail called with a card that is not of this pile")
+	}
+	if c == self.Peek() {
+		return []*Card{c}
+	}
+	for i, pc := range self.cards {
+		if pc == c {
+			return self.cards[i:]
+		}
+	}
+	log.Panic("Pile.MakeTail made an empty tail")
+	return nil
+}
+
+// ApplyToCards applies a function to each card in the pile
+// caller must use a method expression, eg (*Card).FlipUp, yielding a function value
+// with a regular first parameter taking the place of the receiver
+func (self *Pile) ApplyToCards(fn func(*Card)) {
+	for _, c := range self.cards {
+		fn(c)
+	}
+}
+
+// default behaviours for all pile types, that can be over-ridden by providing (eg) *Stock.Collect
+
+func (self *Pile) DefaultTailTapped(tail []*Card) {
+	card := tail[0]
+	if card.tapDestination != nil {
+		src := card.Owner()
+		if len(tail) == 1 {
+			MoveCard(src, card.tapDestination)
+		} else {
+			MoveTail(card, card.tapDestination)
+		}
+	}
+	// don't play an error sound here, leave it up to higher level (Baize.InputTap)
+}
@@ -0,0 +1,139 @@
+package core
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// replayVersion is written into replay files
+const replayVersion = 1
+
+// Replay is one line of play from the deal, with the time each move was made, so it can be watched again
+type Replay struct {
+	Version int
+	Variant string
+	Seed    uint64        `json:",omitempty"`
+	Shuffle ShuffleType   `json:",omitempty"`
+	Won     bool          `json:",omitempty"`
+	Records []*UndoRecord // the first one is the deal
+}
+
+// Replay returns the moves made to get to the current position, from the deal
+func (b *Baize) Replay() *Replay {
+	return &Replay{
+		Version: replayVersion,
+		Variant: b.variant,
+		Seed:    b.seed,
+		Shuffle: b.shuffle,
+		Won:     b.Complete(),
+		Records: append([]*UndoRecord{}, b.undoStack...),
+	}
+}
+
+// Marshal returns the replay as JSON
+func (r *Replay) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// ParseReplay reads a replay from JSON
+func ParseReplay(bytes []byte) (*Replay, error) {
+	var r Replay
+	if err := json.Unmarshal(bytes, &r); err != nil {
+		return nil, err
+	}
+	if r.Version != replayVersion {
+		return nil, fmt.Errorf("Cannot watch a version %d replay", r.Version)
+	}
+	if len(r.Records) == 0 {
+		return nil, errors.New("Replay is empty")
+	}
+	return &r, nil
+}
+
+// Replayer plays a Replay back on a Baize of its own, a move at a time
+type Replayer struct {
+	baize     *Baize
+	replay    *Replay
+	positions []*SavableBaize
+	index     int // the position being shown
+}
+
+// NewReplayer makes a Baize for the variant of the replay, showing the deal
+func NewReplayer(r *Replay, options *Options) (*Replayer, error) {
+	b := NewBaize(r.Variant, options)
+	if b == nil {
+		return nil, fmt.Errorf("Do not know how to play %s", r.Variant)
+	}
+	b.StartFreshGame(r.Seed) // to build the piles
+	if err := b.checkRecords(r.Records); err != nil {
+		return nil, err
+	}
+	var positions []*SavableBaize = make([]*SavableBaize, 0, len(r.Records))
+	var sav *SavableBaize
+	for _, rec := range r.Records {
+		sav = rec.apply(sav)
+		positions = append(positions, sav)
+	}
+	if len(positions) == 0 {
+		return nil, errors.New("Replay has no moves")
+	}
+	b.SetUndoStack(positions[:1])
+	b.seed, b.shuffle = r.Seed, r.Shuffle
+	return &Replayer{baize: b, replay: r, positions: positions}, nil
+}
+
+// Baize returns the Baize the replay is shown on
+func (rp *Replayer) Baize() *Baize {
+	return rp.baize
+}
+
+// Replay returns what is being played back
+func (rp *Replayer) Replay() *Replay {
+	return rp.replay
+}
+
+// Len returns the number of moves in the replay
+func (rp *Replayer) Len() int {
+	return len(rp.positions) - 1
+}
+
+// Index returns the number of moves that have been shown
+func (rp *Replayer) Index() int {
+	return rp.index
+}
+
+// Goto shows the position after n moves
+func (rp *Replayer) Goto(n int) error {
+	if n < 0 || n >= len(rp.positions) {
+		return errors.New("No such move")
+	}
+	rp.index = n
+	rp.baize.SetPosition(rp.positions[n])
+	return nil
+}
+
+// Step shows the next move, returning false at the end of the replay
+func (rp *Replayer) Step() bool {
+	return rp.Goto(rp.index+1) == nil
+}
+
+// Back takes back the last move shown, returning false at the deal
+func (rp *Replayer) Back() bool {
+	return rp.Goto(rp.index-1) == nil
+}
+
+// Delay returns how long the player took over the next move, or 0 if that is not known
+func (rp *Replayer) Delay() time.Duration {
+	if rp.index+1 >= len(rp.replay.Records) {
+		return 0
+	}
+	var from, to int64 = rp.replay.Records[rp.index].Time, rp.replay.Records[rp.index+1].Time
+	if from == 0 || to < from {
+		return 0
+	}
+	return time.Duration(to-from) * time.Millisecond
+}
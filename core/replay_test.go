@@ -0,0 +1,61 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestReplay records a game, then plays it back a move at a time
+func TestReplay(t *testing.T) {
+	b := NewBaize("Freecell", nil)
+	b.StartFreshGame(12345)
+	positions := playRandomMoves(b, 30, 9)
+
+	bytes, err := b.Replay().Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParseReplay(bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp, err := NewReplayer(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rp.Len() != len(positions)-1 || rp.Baize().Variant() != "Freecell" || rp.Baize().Seed() != 12345 {
+		t.Fatalf("replay of %d moves of %s", rp.Len(), rp.Baize().Variant())
+	}
+	if !reflect.DeepEqual(rp.Baize().Position(), positions[0]) {
+		t.Error("replay does not start with the deal")
+	}
+	for i := 1; rp.Step(); i++ {
+		if rp.Index() != i || !reflect.DeepEqual(rp.Baize().Position(), positions[i]) {
+			t.Fatalf("move %d is not as it was played", i)
+		}
+		if rp.Delay() < 0 {
+			t.Errorf("move %d took %v", i, rp.Delay())
+		}
+	}
+	if rp.Index() != rp.Len() {
+		t.Errorf("replay stopped after %d of %d moves", rp.Index(), rp.Len())
+	}
+	rp.Back()
+	if !reflect.DeepEqual(rp.Baize().Position(), positions[len(positions)-2]) {
+		t.Error("stepping back did not take back the last move")
+	}
+
+	for _, bad := range []string{
+		`{"Version": 1, "Variant": "Freecell"}`,
+		`{"Version": 99, "Variant": "Freecell", "Records": [{}]}`,
+		`{"Version": 1, "Variant": "Freecell"`,
+	} {
+		if _, err := ParseReplay([]byte(bad)); err == nil {
+			t.Errorf("%s was accepted", bad)
+		}
+	}
+	r.Variant = "Klondike"
+	if _, err := NewReplayer(r, nil); err == nil {
+		t.Error("played back a Freecell replay as Klondike")
+	}
+}
@@ -1,15 +1,15 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 
 import (
 	"fmt"
 	"log"
-
-	"oddstream.games/gosol/sound"
 )
 
 type ScriptBase struct {
+	baize *Baize
+
 	cells       []*Pile
 	discards    []*Pile
 	foundations []*Pile
@@ -21,6 +21,7 @@ type ScriptBase struct {
 	wikipedia    string
 	cardColors   int
 	packs, suits int
+	jokers       int // per pack
 }
 
 type Scripter interface {
@@ -47,8 +48,13 @@ type Scripter interface {
 	Wikipedia() string
 	CardColors() int
 	SafeCollect() bool
+	MicrosoftDeals() bool
 	Packs() int
 	Suits() int
+	Jokers() int
+
+	setBaize(*Baize)
+	forgetCards()
 }
 
 // fallback/default functions for ScriptBase+Scripter /////////////////////////
@@ -56,6 +62,14 @@ type Scripter interface {
 // no default/fallback for BuildPiles
 // no default/fallback for StartGame
 
+func (sb *ScriptBase) setBaize(b *Baize) {
+	sb.baize = b
+}
+
+// forgetCards is called when all the cards are made afresh, by a deal or by going to another position,
+// so a script that keeps anything for each card can let it go
+func (sb ScriptBase) forgetCards() {}
+
 func (sb ScriptBase) AfterMove() {}
 
 // no default/fallback for TailMoveError
@@ -94,7 +108,7 @@ func (sb ScriptBase) Waste() *Pile {
 	return sb.waste
 }
 
-// Complete - default is number of cards in Foundations == number of cards in CardLibrary.
+// Complete - default is number of cards in Foundations == number of cards in CardLibrary, less any jokers.
 //
 // In Bisley, there may be <13 cards in a Foundation.
 // This will need overriding for any variants with Discard piles.
@@ -104,7 +118,7 @@ func (sb ScriptBase) Complete() bool {
 	for _, f := range sb.foundations {
 		n += len(f.cards)
 	}
-	return n == TheGame.Baize.cardCount
+	return n == sb.baize.homeCount()
 }
 
 // SpiderComplete - used to override default Complete() in Spider varaints.
@@ -148,6 +162,11 @@ func (sb ScriptBase) SafeCollect() bool {
 	return sb.CardColors() == 2
 }
 
+// MicrosoftDeals returns true if this variant can be dealt using Microsoft FreeCell deal numbers
+func (sb ScriptBase) MicrosoftDeals() bool {
+	return false
+}
+
 func (sb ScriptBase) Packs() int {
 	if sb.packs == 0 {
 		return 1
@@ -162,6 +181,12 @@ func (sb ScriptBase) Suits() int {
 	return sb.suits
 }
 
+// Jokers returns the number of jokers in each pack, which are wild cards if the variant's compares
+// are wrapped with Wild; most variants have none
+func (sb ScriptBase) Jokers() int {
+	return sb.jokers
+}
+
 // You can't use functions as keys in maps : the key type must be comparable
 // so you can't do: var ExtendedColorMap = map[CardPairCompareFunc]bool{}
 // type CardPairCompareFunc func(CardPair) (bool, error)
@@ -182,7 +207,6 @@ func MoveCard(src *Pile, dst *Pile) *Card {
 	if c := src.Pop(); c != nil {
 		dst.Push(c)
 		src.FlipUpExposedCard()
-		sound.Play("Place")
 		return c
 	}
 	return nil
@@ -211,25 +235,25 @@ func MoveTail(card *Card, dst *Pile) {
 			dst.Push(c)
 		}
 		src.FlipUpExposedCard()
-		sound.Play("Place")
 	}
 }
 
 func RecycleWasteToStock(waste *Pile, stock *Pile) {
-	if TheGame.Baize.Recycles() > 0 {
+	var b *Baize = stock.baize
+	if b.Recycles() > 0 {
 		for waste.Len() > 0 {
 			MoveCard(waste, stock)
 		}
-		TheGame.Baize.SetRecycles(TheGame.Baize.Recycles() - 1)
+		b.SetRecycles(b.Recycles() - 1)
 		switch {
-		case TheGame.Baize.recycles == 0:
-			TheGame.UI.ToastInfo("No more recycles")
-		case TheGame.Baize.recycles == 1:
-			TheGame.UI.ToastInfo(fmt.Sprintf("%d recycle remaining", TheGame.Baize.Recycles()))
-		case TheGame.Baize.recycles < 10:
-			TheGame.UI.ToastInfo(fmt.Sprintf("%d recycles remaining", TheGame.Baize.Recycles()))
+		case b.recycles == 0:
+			b.toastInfo("No more recycles")
+		case b.recycles == 1:
+			b.toastInfo(fmt.Sprintf("%d recycle remaining", b.Recycles()))
+		case b.recycles < 10:
+			b.toastInfo(fmt.Sprintf("%d recycles remaining", b.Recycles()))
 		}
 	} else {
-		TheGame.UI.ToastInfo("No more recycles")
+		b.toastInfo("No more recycles")
 	}
 }
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// splitMix is a rand.Source64 that uses all 64 bits of its seed, so every deal number gives its own deal;
+// rand.NewSource reduces the seed mod 2^31-1, so deals 5 and 2147483652 would be the same.
+// see https://prng.di.unimi.it/splitmix64.c
+type splitMix struct {
+	state uint64
+}
+
+func newSplitMix(seed uint64) *splitMix {
+	return &splitMix{state: seed}
+}
+
+func (s *splitMix) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+func (s *splitMix) Uint64() uint64 {
+	s.state += 0x9E3779B97F4A7C15
+	var z uint64 = s.state
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
+
+func (s *splitMix) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// seedRand makes the random deal numbers; one source, seeded once, shared by every game
+var seedRand struct {
+	sync.Mutex
+	*rand.Rand
+}
+
+func init() {
+	seedRand.Rand = rand.New(newSplitMix(uint64(time.Now().UnixNano())))
+}
+
+// NewSeed returns a random deal number
+func NewSeed() uint64 {
+	seedRand.Lock()
+	defer seedRand.Unlock()
+	var seed uint64
+	for seed == 0 {
+		seed = seedRand.Uint64()
+	}
+	return seed
+}
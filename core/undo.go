@@ -0,0 +1,449 @@
+package core
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// The CardID contains everything we need to serialize the card: pack, ordinal, suit and prone flag
+
+type SavablePile struct {
+	Category string          // for readability and sanity checks
+	Label    string          `json:",omitempty"`
+	Cards    []cardid.CardID `json:",omitempty"`
+}
+
+type SavableBaize struct {
+	Piles    []*SavablePile `json:",omitempty"`
+	Bookmark int            `json:",omitempty"` // only in old save files; the bookmark is now kept in SavableGame
+	Recycles int            `json:",omitempty"`
+	Seed     uint64         `json:",omitempty"`
+	Shuffle  ShuffleType    `json:",omitempty"`
+	Winnable bool           `json:",omitempty"`
+}
+
+// undoKeyframeInterval is how often the whole position is kept in the history
+const undoKeyframeInterval = 32
+
+// savableGameVersion is written into save files; older save files were a JSON array of SavableBaize
+const savableGameVersion = 1
+
+// PileChange is how one pile was changed by a move: the cards above the bottom Keep cards
+// were replaced by Cards (which includes any cards that were flipped)
+type PileChange struct {
+	Pile  int
+	Keep  int             `json:",omitempty"`
+	Cards []cardid.CardID `json:",omitempty"`
+	Label *string         `json:",omitempty"` // nil if the label did not change
+}
+
+// UndoRecord is one position in the history of a game; either the whole position (a keyframe),
+// or what changed since the position before it
+type UndoRecord struct {
+	Keyframe *SavableBaize `json:",omitempty"`
+	Changes  []PileChange  `json:",omitempty"`
+	Recycles int           `json:",omitempty"` // change in the number of recycles left
+	Time     int64         `json:",omitempty"` // when the position was reached, in Unix milliseconds
+}
+
+// SavableBranch is one line of play, as saved to file; it only keeps the positions after it left its parent
+type SavableBranch struct {
+	Parent  int           `json:",omitempty"` // the branch this one was made from
+	Fork    int           `json:",omitempty"` // number of positions it shares with its parent
+	Records []*UndoRecord `json:",omitempty"`
+	Percent int           `json:",omitempty"`
+}
+
+// SavableGame is what gets saved to file: every line of play, where the player is, and the bookmarks
+type SavableGame struct {
+	Version   int
+	Bookmarks []Bookmark       `json:",omitempty"`
+	Branch    int              `json:",omitempty"` // the branch being played
+	Depth     int              `json:",omitempty"` // number of positions of that branch that have not been undone
+	Branches  []*SavableBranch `json:",omitempty"`
+}
+
+// branch is one line of play from the deal. Undoing some moves and then making a different one
+// starts a new branch, so both lines can be gone back to. Branches share the records they have in common.
+type branch struct {
+	records      []*UndoRecord
+	parent, fork int
+	percent      int // how complete the last position of this branch is
+}
+
+// BranchInfo describes a branch, so the player can choose one
+type BranchInfo struct {
+	Moves   int  // number of moves to the end of the branch
+	Percent int  // how complete the position at the end of the branch is
+	Current bool // true if this is the branch being played
+}
+
+func (self *Pile) savable() *SavablePile {
+	sp := &SavablePile{Category: self.category, Label: self.label}
+	for _, c := range self.cards {
+		sp.Cards = append(sp.Cards, c.id)
+	}
+	return sp
+}
+
+func (self *Pile) updateFromSavable(sp *SavablePile) {
+	if self.category != sp.Category {
+		log.Panicf("Baize pile (%s) and SavablePile (%s) are different", self.category, sp.Category)
+	}
+	self.Reset()
+	// card is created same face up/down as it was saved
+	for _, cid := range sp.Cards {
+		var c Card = Card{id: cid}
+		self.Push(&c) // will always flip down if pile is Stock
+	}
+	if len(self.cards) != len(sp.Cards) {
+		log.Panicf("%s cards rebuilt incorrectly", self.category)
+	}
+	self.SetLabel(sp.Label)
+}
+
+func (b *Baize) newSavableBaize() *SavableBaize {
+	sb := &SavableBaize{Recycles: b.recycles, Seed: b.seed, Shuffle: b.shuffle, Winnable: b.winnable}
+	for _, p := range b.piles {
+		sb.Piles = append(sb.Piles, p.savable())
+	}
+	return sb
+}
+
+// Position returns a copy of where all the cards are now, without adding it to the history
+func (b *Baize) Position() *SavableBaize {
+	return b.newSavableBaize()
+}
+
+// SetPosition moves the cards to where sav says they are, without changing the history
+// or looking for moves; a solver uses it to go back and try something else
+func (b *Baize) SetPosition(sav *SavableBaize) {
+	b.updateFromSavable(sav)
+}
+
+// UndoPush records the position after a move.
+// Every undoKeyframeInterval positions the whole position is kept, in between only the changes.
+// If moves have been undone, and this is not the move that would be redone, a new branch is started.
+func (b *Baize) UndoPush() {
+	sav := b.newSavableBaize()
+	rec := &UndoRecord{Time: time.Now().UnixMilli()}
+	if len(b.undoStack)%undoKeyframeInterval == 0 {
+		rec.Keyframe = sav
+	} else {
+		rec.Changes = changes(b.current, sav)
+		rec.Recycles = sav.Recycles - b.current.Recycles
+	}
+
+	var depth int = len(b.undoStack)
+	switch {
+	case len(b.branches) == 0:
+		b.branches = []*branch{{records: []*UndoRecord{rec}}}
+		b.branch = 0
+	case depth == len(b.branches[b.branch].records):
+		// at the end of the branch, so the branch grows
+		b.branches[b.branch].records = append(b.branches[b.branch].records, rec)
+	case reflect.DeepEqual(b.branches[b.branch].records[depth].apply(b.current), sav):
+		// the same move as the next one in the branch, so treat it as a redo,
+		// made now rather than when it was first made, so replays keep the right pace
+		rec = b.retime(depth)
+		sav = rec.apply(b.current)
+	default:
+		var records []*UndoRecord = make([]*UndoRecord, 0, depth+1)
+		records = append(records, b.undoStack...)
+		b.branches = append(b.branches, &branch{records: append(records, rec), parent: b.branch, fork: depth})
+		b.branch = len(b.branches) - 1
+	}
+	b.undoStack = b.branches[b.branch].records[:depth+1]
+	b.current = sav
+	if depth+1 == len(b.branches[b.branch].records) {
+		b.branches[b.branch].percent = b.PercentComplete()
+	}
+}
+
+// retime gives the i'th record of the branch being played the time now, as its move has just been made again;
+// the record is copied, as other branches may share it
+func (b *Baize) retime(i int) *UndoRecord {
+	var rec UndoRecord = *b.branches[b.branch].records[i]
+	rec.Time = time.Now().UnixMilli()
+	b.branches[b.branch].records[i] = &rec
+	return &rec
+}
+
+// UndoLen returns the number of positions in the history of this game, including the deal
+func (b *Baize) UndoLen() int {
+	return len(b.undoStack)
+}
+
+// RedoLen returns the number of moves that can be redone
+func (b *Baize) RedoLen() int {
+	if len(b.branches) == 0 {
+		return 0
+	}
+	return len(b.branches[b.branch].records) - len(b.undoStack)
+}
+
+// UndoStack returns the history of this game, oldest position first.
+// The positions are rebuilt from the undo records, so this is not cheap.
+func (b *Baize) UndoStack() []*SavableBaize {
+	var stack []*SavableBaize = make([]*SavableBaize, 0, len(b.undoStack))
+	var sav *SavableBaize
+	for _, rec := range b.undoStack {
+		sav = rec.apply(sav)
+		stack = append(stack, sav)
+	}
+	return stack
+}
+
+// UndoPeek returns the current position, which is the last one in the history;
+// a new one is made after every move
+func (b *Baize) UndoPeek() *SavableBaize {
+	return b.current
+}
+
+// position rebuilds the i'th position of the branch being played, starting from the keyframe before it
+func (b *Baize) position(i int) *SavableBaize {
+	var records []*UndoRecord = b.branches[b.branch].records
+	var k int = i
+	for records[k].Keyframe == nil {
+		k--
+	}
+	var sav *SavableBaize
+	for ; k <= i; k++ {
+		sav = records[k].apply(sav)
+	}
+	return sav
+}
+
+// goTo goes to the n'th position of branch br; the positions after it are kept, to be redone
+func (b *Baize) goTo(br int, n int) {
+	b.branch = br
+	b.undoStack = b.branches[br].records[:n]
+	b.current = b.position(n - 1)
+	b.updateFromSavable(b.current)
+	b.FindDestinations()
+}
+
+// undoTo goes back to the n'th position of the branch being played
+func (b *Baize) undoTo(n int) {
+	b.goTo(b.branch, n)
+}
+
+// Branches describes every line of play in this game, in the order they were started
+func (b *Baize) Branches() []BranchInfo {
+	var infos []BranchInfo = make([]BranchInfo, 0, len(b.branches))
+	for i, br := range b.branches {
+		infos = append(infos, BranchInfo{Moves: len(br.records) - 1, Percent: br.percent, Current: i == b.branch})
+	}
+	return infos
+}
+
+// GotoBranch goes to the end of another line of play
+func (b *Baize) GotoBranch(i int) error {
+	if i < 0 || i >= len(b.branches) {
+		return errors.New("No such branch")
+	}
+	if b.Complete() {
+		return errors.New("Cannot leave a completed game") // otherwise the stats can be cooked
+	}
+	b.goTo(i, len(b.branches[i].records))
+	return nil
+}
+
+// changes returns the changes to the piles that turn position prev into next
+func changes(prev, next *SavableBaize) []PileChange {
+	var pcs []PileChange
+	for i, np := range next.Piles {
+		pp := prev.Piles[i]
+		var keep int
+		for keep < len(pp.Cards) && keep < len(np.Cards) && pp.Cards[keep] == np.Cards[keep] {
+			keep++
+		}
+		if keep == len(pp.Cards) && keep == len(np.Cards) && pp.Label == np.Label {
+			continue
+		}
+		pc := PileChange{Pile: i, Keep: keep, Cards: np.Cards[keep:]}
+		if len(pc.Cards) == 0 {
+			pc.Cards = nil
+		}
+		if pp.Label != np.Label {
+			var label string = np.Label
+			pc.Label = &label
+		}
+		pcs = append(pcs, pc)
+	}
+	return pcs
+}
+
+// apply returns the position this record leads to from the position before it;
+// the position before is not changed
+func (rec *UndoRecord) apply(prev *SavableBaize) *SavableBaize {
+	if rec.Keyframe != nil {
+		return rec.Keyframe
+	}
+	var sav SavableBaize = *prev
+	sav.Recycles += rec.Recycles
+	sav.Piles = make([]*SavablePile, len(prev.Piles))
+	copy(sav.Piles, prev.Piles)
+	for _, pc := range rec.Changes {
+		var sp SavablePile = *sav.Piles[pc.Pile]
+		// the full slice expression makes append copy, rather than write over the cards of prev
+		sp.Cards = append(sp.Cards[:pc.Keep:pc.Keep], pc.Cards...)
+		if len(sp.Cards) == 0 {
+			sp.Cards = nil
+		}
+		if pc.Label != nil {
+			sp.Label = *pc.Label
+		}
+		sav.Piles[pc.Pile] = &sp
+	}
+	return &sav
+}
+
+// UndoRecords returns the history of this game, as it is kept
+func (b *Baize) UndoRecords() []*UndoRecord {
+	return b.undoStack
+}
+
+// SavableGame returns this game, ready to be saved to file
+func (b *Baize) SavableGame() *SavableGame {
+	sg := &SavableGame{
+		Version:   savableGameVersion,
+		Bookmarks: b.bookmarks,
+		Branch:    b.branch,
+		Depth:     len(b.undoStack),
+	}
+	for _, br := range b.branches {
+		sg.Branches = append(sg.Branches, &SavableBranch{Parent: br.parent, Fork: br.fork, Records: br.records[br.fork:], Percent: br.percent})
+	}
+	return sg
+}
+
+// SetSavableGame replaces this game with one loaded from file,
+// and moves the cards to where the player was
+func (b *Baize) SetSavableGame(sg *SavableGame) error {
+	if sg.Version != savableGameVersion {
+		return fmt.Errorf("Cannot load a version %d saved game", sg.Version)
+	}
+
+	var branches []*branch
+	for i, sb := range sg.Branches {
+		br := &branch{parent: sb.Parent, fork: sb.Fork, percent: sb.Percent}
+		if i > 0 {
+			if sb.Parent < 0 || sb.Parent >= i || sb.Fork < 1 || sb.Fork > len(branches[sb.Parent].records) {
+				return errors.New("Saved game has a bad branch")
+			}
+			br.records = append(br.records, branches[sb.Parent].records[:sb.Fork]...)
+		}
+		br.records = append(br.records, sb.Records...)
+		if err := b.checkRecords(br.records); err != nil {
+			return err
+		}
+		branches = append(branches, br)
+	}
+	if len(branches) == 0 || sg.Branch < 0 || sg.Branch >= len(branches) || sg.Depth < 1 || sg.Depth > len(branches[sg.Branch].records) {
+		return errors.New("Saved game is empty")
+	}
+	b.branches = branches
+	b.goTo(sg.Branch, sg.Depth)
+	b.setBookmarks(sg.Bookmarks)
+	return nil
+}
+
+// checkRecords checks that the records of a branch fit the piles and each other, by rebuilding the positions
+func (b *Baize) checkRecords(records []*UndoRecord) error {
+	var sav *SavableBaize
+	for _, rec := range records {
+		if rec.Keyframe != nil {
+			if !b.isSavableOk(rec.Keyframe) {
+				return errors.New("Saved game does not fit the piles")
+			}
+		} else if sav == nil {
+			return errors.New("Saved game does not start with a keyframe")
+		} else {
+			for _, pc := range rec.Changes {
+				if pc.Pile < 0 || pc.Pile >= len(sav.Piles) || pc.Keep < 0 || pc.Keep > len(sav.Piles[pc.Pile].Cards) {
+					return errors.New("Saved game has a bad move")
+				}
+			}
+		}
+		sav = rec.apply(sav)
+	}
+	return nil
+}
+
+// UnmarshalGame replaces this game with one saved by MarshalGame,
+// or an old save file that kept every position
+func (b *Baize) UnmarshalGame(bytes []byte) error {
+	if trimmed := strings.TrimSpace(string(bytes)); strings.HasPrefix(trimmed, "[") {
+		var undoStack []*SavableBaize
+		if err := json.Unmarshal(bytes, &undoStack); err != nil {
+			return err
+		}
+		if !b.IsSavableStackOk(undoStack) {
+			return errors.New("Saved undo stack does not fit the piles")
+		}
+		return b.SetUndoStack(undoStack)
+	}
+	var sg SavableGame
+	if err := json.Unmarshal(bytes, &sg); err != nil {
+		return err
+	}
+	return b.SetSavableGame(&sg)
+}
+
+// MarshalGame returns this game as JSON
+func (b *Baize) MarshalGame() ([]byte, error) {
+	return json.Marshal(b.SavableGame())
+}
+
+func (b *Baize) isSavableOk(sb *SavableBaize) bool {
+	if len(b.piles) != len(sb.Piles) {
+		log.Printf("Baize piles (%d) and SavableBaize piles (%d) are different", len(b.piles), len(sb.Piles))
+		return false
+	}
+	for i := 0; i < len(sb.Piles); i++ {
+		if b.piles[i].category != sb.Piles[i].Category {
+			log.Printf("Baize pile (%s) and SavablePile (%s) are different", b.piles[i].category, sb.Piles[i].Category)
+			return false
+		}
+	}
+	return true
+}
+
+// IsSavableStackOk checks that a (loaded) undo stack fits the piles of this Baize
+func (b *Baize) IsSavableStackOk(stack []*SavableBaize) bool {
+	if len(stack) == 0 {
+		log.Print("No savable stack")
+		return false
+	}
+	for i := 0; i < len(stack); i++ {
+		if !b.isSavableOk(stack[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Baize) updateFromSavable(sb *SavableBaize) {
+	if len(b.piles) != len(sb.Piles) {
+		log.Panicf("Baize piles (%d) and SavableBaize piles (%d) are different", len(b.piles), len(sb.Piles))
+	}
+	b.script.forgetCards()
+	for i := 0; i < len(sb.Piles); i++ {
+		b.piles[i].updateFromSavable(sb.Piles[i])
+	}
+	b.recycles = sb.Recycles
+	b.seed = sb.Seed
+	b.shuffle = sb.Shuffle
+	b.winnable = sb.Winnable
+}
@@ -0,0 +1,260 @@
+package core
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// playRandomMoves makes up to n moves, chosen at random, as a player would,
+// and returns the position after the deal and after each move
+func playRandomMoves(b *Baize, n int, seed int64) []*SavableBaize {
+	var rng *rand.Rand = rand.New(rand.NewSource(seed))
+	var positions []*SavableBaize = []*SavableBaize{b.Position()}
+	for i := 0; i < n && !b.Complete(); i++ {
+		var tails []*MovableTail
+		for _, p := range b.Piles() {
+			tails = append(tails, p.Vtable().MovableTails()...)
+		}
+		var stock *Pile = b.Script().Stock()
+		if len(tails) == 0 || (!stock.Hidden() && !stock.Empty() && rng.Intn(4) == 0) {
+			if stock.Hidden() || stock.Empty() {
+				break
+			}
+			b.TailTapped([]*Card{stock.Peek()})
+		} else {
+			mt := tails[rng.Intn(len(tails))]
+			if err := b.MoveTailTo(mt.Tail(), mt.Dst()); err != nil {
+				continue
+			}
+		}
+		b.AfterUserMove()
+		positions = append(positions, b.Position())
+	}
+	return positions
+}
+
+// TestUndoRecords checks that the positions can be rebuilt from the undo records,
+// after saving and loading too
+func TestUndoRecords(t *testing.T) {
+	for _, name := range []string{"Klondike", "Spider Two Suits", "Freecell", "Canfield"} {
+		b := NewBaize(name, nil)
+		b.StartFreshGame(12345)
+		positions := playRandomMoves(b, 100, 1)
+		if b.UndoLen() != len(positions) {
+			t.Fatalf("%s: %d undo records for %d positions", name, b.UndoLen(), len(positions))
+		}
+		if !reflect.DeepEqual(b.UndoStack(), positions) {
+			t.Errorf("%s: undo stack does not match the positions played", name)
+		}
+
+		bytes, err := b.MarshalGame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		old, _ := json.MarshalIndent(positions, "", "\t")
+		if len(bytes)*4 > len(old) {
+			t.Errorf("%s: saved game is %d bytes, old style was %d", name, len(bytes), len(old))
+		}
+
+		b2 := NewBaize(name, nil)
+		b2.StartFreshGame(1)
+		if err := b2.UnmarshalGame(bytes); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(b2.UndoStack(), positions) {
+			t.Errorf("%s: loaded undo stack does not match the positions played", name)
+		}
+
+		for i := len(positions) - 2; i >= 0; i-- {
+			if err := b2.Undo(); err != nil {
+				t.Fatalf("%s: %s", name, err)
+			}
+			if !reflect.DeepEqual(b2.Position(), positions[i]) {
+				t.Fatalf("%s: position %d is wrong after undo", name, i)
+			}
+		}
+	}
+}
+
+// TestLoadOldSaveFile checks that a game saved as a list of positions still loads
+func TestLoadOldSaveFile(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame(12345)
+	positions := playRandomMoves(b, 20, 2)
+	positions[len(positions)-1].Bookmark = 3
+	old, _ := json.MarshalIndent(positions, "", "\t")
+
+	b2 := NewBaize("Klondike", nil)
+	b2.StartFreshGame(1)
+	if err := b2.UnmarshalGame(old); err != nil {
+		t.Fatal(err)
+	}
+	if bms := b2.Bookmarks(); len(bms) != 1 || bms[0].Depth != 3 || bms[0].Name != "Move 2" || b2.UndoLen() != len(positions) {
+		t.Errorf("loaded bookmarks %v, %d positions", bms, b2.UndoLen())
+	}
+	positions[len(positions)-1].Bookmark = 0
+	if !reflect.DeepEqual(b2.UndoStack(), positions) {
+		t.Error("loaded undo stack does not match the positions played")
+	}
+
+	if err := b2.UnmarshalGame([]byte(`{"Version": 1, "Depth": 1, "Branches": [{"Records": [{"Changes": [{"Pile": 1}]}]}]}`)); err == nil {
+		t.Error("loaded a game without a keyframe")
+	}
+	if err := b2.UnmarshalGame([]byte(`[]`)); err == nil {
+		t.Error("loaded an old save file without any positions")
+	}
+	if err := b2.SetUndoStack(nil); err == nil {
+		t.Error("set an empty undo stack")
+	}
+}
+
+// TestRedo checks that undone moves can be redone, even after saving and loading,
+// until another move is made
+func TestRedo(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame(12345)
+	positions := playRandomMoves(b, 60, 1)
+	var n int = len(positions)
+	for i := 0; i < 40; i++ {
+		if err := b.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := n - 40; i < n-10; i++ {
+		if err := b.Redo(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(b.Position(), positions[i]) {
+			t.Fatalf("position %d is wrong after redo", i)
+		}
+	}
+	if b.RedoLen() != 10 {
+		t.Errorf("%d moves to redo", b.RedoLen())
+	}
+
+	bytes, err := b.MarshalGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b2 := NewBaize("Klondike", nil)
+	b2.StartFreshGame(1)
+	if err := b2.UnmarshalGame(bytes); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(b2.Position(), positions[n-11]) || b2.RedoLen() != 10 {
+		t.Fatalf("loaded game has %d moves to redo", b2.RedoLen())
+	}
+	for b2.RedoLen() > 0 {
+		b2.Redo()
+	}
+	if !reflect.DeepEqual(b2.UndoStack(), positions) {
+		t.Error("redoing every move did not get back to where the game was")
+	}
+	if err := b2.Redo(); err == nil {
+		t.Error("redid a move that was not undone")
+	}
+
+	b.Undo()
+	playRandomMoves(b, 1, 6)
+	if b.RedoLen() != 0 {
+		t.Error("can still redo after making a move")
+	}
+}
+
+// TestRedoTime checks that a move redone is timed when it was redone, not when it was first made
+func TestRedoTime(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame(12345)
+	playRandomMoves(b, 10, 1)
+	var last int = b.UndoLen() - 1
+	b.UndoRecords()[last].Time = 1
+	b.Undo()
+	b.Redo()
+	if b.UndoRecords()[last].Time == 1 {
+		t.Error("redo kept the time the move was first made")
+	}
+}
+
+// TestRestartDeal checks that restarting goes back to the deal and forgets the moves, branches and bookmarks
+func TestRestartDeal(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame(12345)
+	positions := playRandomMoves(b, 20, 1)
+	b.AddBookmark()
+	for i := 0; i < 5; i++ {
+		b.Undo()
+	}
+	if err := b.RestartDeal(); err != nil {
+		t.Fatal(err)
+	}
+	if b.UndoLen() != 1 || b.RedoLen() != 0 || len(b.Branches()) != 1 || len(b.Bookmarks()) != 0 {
+		t.Errorf("after restart, %d positions, %d to redo, %d branches, %d bookmarks", b.UndoLen(), b.RedoLen(), len(b.Branches()), len(b.Bookmarks()))
+	}
+	if !reflect.DeepEqual(b.Position(), positions[0]) {
+		t.Error("restart did not go back to the deal")
+	}
+	playRandomMoves(b, 5, 2)
+	if b.UndoLen() != 6 || len(b.Branches()) != 1 {
+		t.Errorf("after restart and 5 moves, %d positions and %d branches", b.UndoLen(), len(b.Branches()))
+	}
+}
+
+// TestBranches checks that undoing and then making a different move keeps both lines of play
+func TestBranches(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame(12345)
+	first := playRandomMoves(b, 50, 7)
+	for i := 0; i < 40; i++ {
+		b.Undo()
+	}
+	// keep trying until a move is made that differs from the one undone
+	for seed := int64(8); len(b.Branches()) == 1; seed++ {
+		if b.RedoLen() < 40 {
+			b.Undo()
+		}
+		playRandomMoves(b, 1, seed)
+	}
+	playRandomMoves(b, 30, 8)
+	second := b.UndoStack()
+
+	branches := b.Branches()
+	if len(branches) != 2 || !branches[1].Current || branches[0].Moves != len(first)-1 || branches[1].Moves != len(second)-1 {
+		t.Fatalf("branches %v", branches)
+	}
+	if b.RedoLen() != 0 {
+		t.Errorf("%d moves to redo at the end of a new branch", b.RedoLen())
+	}
+
+	bytes, err := b.MarshalGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b2 := NewBaize("Klondike", nil)
+	b2.StartFreshGame(1)
+	if err := b2.UnmarshalGame(bytes); err != nil {
+		t.Fatal(err)
+	}
+	for _, bb := range []*Baize{b, b2} {
+		if err := bb.GotoBranch(0); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(bb.UndoStack(), first) {
+			t.Error("first branch is not as it was played")
+		}
+		if err := bb.GotoBranch(1); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(bb.UndoStack(), second) {
+			t.Error("second branch is not as it was played")
+		}
+	}
+
+	// undoing and redoing does not start a branch
+	b.Undo()
+	b.Redo()
+	if b.RedoLen() != 0 || len(b.Branches()) != 2 {
+		t.Errorf("undo and redo made %d branches", len(b.Branches()))
+	}
+}
@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 I'll call the receiver anything I like, thank you
@@ -56,26 +56,26 @@ type Antares struct {
 
 func (self *Antares) BuildPiles() {
 
-	self.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
 
 	self.cells = nil
 	for x := 0; x < 4; x++ {
-		self.cells = append(self.cells, NewCell(image.Point{x, 0}))
+		self.cells = append(self.cells, NewCell(self.baize, image.Point{x, 0}))
 	}
 
 	self.foundations = nil
 	for x := 5; x < 9; x++ {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
 
 	self.tableaux = nil
 	for x := 0; x < 4; x++ {
-		self.tableaux = append(self.tableaux, NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ONE_PLUS))
+		self.tableaux = append(self.tableaux, NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ONE_PLUS))
 	}
 	for x := 5; x < 9; x++ {
-		self.tableaux = append(self.tableaux, NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY))
+		self.tableaux = append(self.tableaux, NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY))
 	}
 }
 
@@ -92,7 +92,7 @@ func (self *Antares) StartGame() {
 		}
 	}
 
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
 
 	if DebugMode && self.stock.Len() > 0 {
 		log.Println("*** still", self.stock.Len(), "cards in Stock ***")
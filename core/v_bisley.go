@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 I'll call the receiver anything I like, thank you
@@ -15,25 +15,25 @@ type Bisley struct {
 
 func (self *Bisley) BuildPiles() {
 
-	self.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
 
 	self.foundations = nil
 
 	for x := 0; x < 4; x++ {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("K")
 	}
 
 	for x := 0; x < 4; x++ {
-		f := NewFoundation(image.Point{x, 1})
+		f := NewFoundation(self.baize, image.Point{x, 1})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
 
 	self.tableaux = nil
 	for x := 0; x < 13; x++ {
-		t := NewTableau(image.Point{x, 2}, FAN_DOWN, MOVE_ONE)
+		t := NewTableau(self.baize, image.Point{x, 2}, FAN_DOWN, MOVE_ONE)
 		self.tableaux = append(self.tableaux, t)
 		t.SetLabel("X")
 	}
@@ -59,7 +59,7 @@ func (self *Bisley) StartGame() {
 		}
 	}
 
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
 }
 
 func (*Bisley) TailMoveError(tail []*Card) (bool, error) {
@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 Receiver name will be anything I like, thank you
@@ -21,21 +21,21 @@ type Canfield struct {
 
 func (self *Canfield) BuildPiles() {
 
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
 
-	self.waste = NewWaste(image.Point{1, 0}, FAN_RIGHT3)
+	self.waste = NewWaste(self.baize, image.Point{1, 0}, FAN_RIGHT3)
 
 	self.reserves = nil
-	self.reserves = append(self.reserves, NewReserve(image.Point{0, 1}, FAN_DOWN))
+	self.reserves = append(self.reserves, NewReserve(self.baize, image.Point{0, 1}, FAN_DOWN))
 
 	self.foundations = nil
 	for x := 3; x < 7; x++ {
-		self.foundations = append(self.foundations, NewFoundation(image.Point{x, 0}))
+		self.foundations = append(self.foundations, NewFoundation(self.baize, image.Point{x, 0}))
 	}
 
 	self.tableaux = nil
 	for x := 3; x < 7; x++ {
-		self.tableaux = append(self.tableaux, NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ONE_OR_ALL))
+		self.tableaux = append(self.tableaux, NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ONE_OR_ALL))
 	}
 }
 
@@ -70,7 +70,7 @@ func (self *Canfield) StartGame() {
 		MoveCard(self.stock, pile)
 	}
 
-	TheGame.Baize.SetRecycles(self.recycles)
+	self.baize.SetRecycles(self.recycles)
 }
 
 func (self *Canfield) AfterMove() {
@@ -0,0 +1,415 @@
+package core
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+//lint:file-ignore ST1006 I'll call the receiver anything I like, thank you
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"strings"
+)
+
+// VariantDefinition describes a variant in a file, so it can be played without recompiling.
+// It is read from JSON, for example
+//
+//	{
+//		"Name": "Little Klondike",
+//		"Tableau": "DownAltColor",
+//		"Foundation": "UpSuit",
+//		"Recycles": 2,
+//		"Piles": [
+//			{"Type": "Stock", "Slot": [0, 0]},
+//			{"Type": "Waste", "Slot": [1, 0], "Fan": "Right3"},
+//			{"Type": "Foundation", "Slot": [3, 0], "Count": 4, "Label": "A"},
+//			{"Type": "Tableau", "Slot": [0, 1], "Count": 7, "Fan": "Down", "Move": "Any", "Label": "K",
+//				"Deal": ["u", "du", "ddu", "dddu", "ddddu", "dddddu", "ddddddu"]}
+//		]
+//	}
+type VariantDefinition struct {
+	Name         string
+	Groups       []string // extra groups to show the variant in, eg "> Klondikes"; it is always in "> Custom"
+	Wikipedia    string
+	CardColors   int
+	Packs, Suits int
+	Jokers       int // per pack; they are wild if the compares below have Wild after them, eg "DownAltColorWild", and never go home
+	Recycles     int
+	Draw         int    // number of cards moved from the Stock to the Waste with each tap, default 1
+	Tableau      string // how cards build on a tableau, eg "DownAltColor" for CardPair.Compare_DownAltColor
+	TableauMove  string // how cards in a tail moved from a tableau must be sorted; default is the same as Tableau, "Any" for unsorted
+	Foundation   string // how cards build on a foundation, default "UpSuit"
+	Piles        []PileDefinition
+}
+
+// PileDefinition describes one or more piles of the same type, in a row or column
+type PileDefinition struct {
+	Type  string   // Stock, Waste, Cell, Reserve, Foundation, Tableau or Discard
+	Slot  [2]int   // of the first pile; a Stock with a negative slot is hidden
+	Count int      // number of piles, default 1
+	Step  [2]int   // added to the slot of each pile to get the slot of the next, default [1, 0]
+	Fan   string   // None, Down, Left, Right, Down3, Left3 or Right3
+	Move  string   // tableau only: None, Any, One, OnePlus or OneOrAll, default Any
+	Label string   // the card an empty pile accepts, eg "K", or "X" for none
+	Deal  []string // cards dealt to each pile at the start, "d" face down and "u" face up; the last pattern is used for any remaining piles
+}
+
+// pairCompareFuncs maps the names used in a VariantDefinition to the functions that compare cards
+var pairCompareFuncs = map[string]CardPairCompareFunc{
+	"Up":               CardPair.Compare_Up,
+	"UpWrap":           CardPair.Compare_UpWrap,
+	"Down":             CardPair.Compare_Down,
+	"DownWrap":         CardPair.Compare_DownWrap,
+	"UpOrDown":         CardPair.Compare_UpOrDown,
+	"UpOrDownWrap":     CardPair.Compare_UpOrDownWrap,
+	"Color":            CardPair.Compare_Color,
+	"AltColor":         CardPair.Compare_AltColor,
+	"Suit":             CardPair.Compare_Suit,
+	"OtherSuit":        CardPair.Compare_OtherSuit,
+	"DownColor":        CardPair.Compare_DownColor,
+	"DownAltColor":     CardPair.Compare_DownAltColor,
+	"DownColorWrap":    CardPair.Compare_DownColorWrap,
+	"DownAltColorWrap": CardPair.Compare_DownAltColorWrap,
+	"UpAltColor":       CardPair.Compare_UpAltColor,
+	"UpSuit":           CardPair.Compare_UpSuit,
+	"DownSuit":         CardPair.Compare_DownSuit,
+	"UpOrDownSuit":     CardPair.Compare_UpOrDownSuit,
+	"UpOrDownSuitWrap": CardPair.Compare_UpOrDownSuitWrap,
+	"DownOtherSuit":    CardPair.Compare_DownOtherSuit,
+	"UpSuitWrap":       CardPair.Compare_UpSuitWrap,
+	"DownSuitWrap":     CardPair.Compare_DownSuitWrap,
+}
+
+var fanTypes = map[string]FanType{
+	"":       FAN_NONE,
+	"None":   FAN_NONE,
+	"Down":   FAN_DOWN,
+	"Left":   FAN_LEFT,
+	"Right":  FAN_RIGHT,
+	"Down3":  FAN_DOWN3,
+	"Left3":  FAN_LEFT3,
+	"Right3": FAN_RIGHT3,
+}
+
+var moveTypes = map[string]MoveType{
+	"":         MOVE_ANY,
+	"None":     MOVE_NONE,
+	"Any":      MOVE_ANY,
+	"One":      MOVE_ONE,
+	"OnePlus":  MOVE_ONE_PLUS,
+	"OneOrAll": MOVE_ONE_OR_ALL,
+}
+
+// Declared is the script for a variant read from a VariantDefinition
+type Declared struct {
+	ScriptBase
+	def                                *VariantDefinition
+	tabCompareFunc, tabMoveCompareFunc CardPairCompareFunc
+	foundCompareFunc                   CardPairCompareFunc
+	dealt                              []dealPattern
+}
+
+// dealPattern is what is dealt to a pile at the start of a game
+type dealPattern struct {
+	pile    *Pile
+	pattern string
+}
+
+// ParseVariant reads a VariantDefinition from JSON, and checks it makes sense
+func ParseVariant(bytes []byte) (*VariantDefinition, error) {
+	var def *VariantDefinition = &VariantDefinition{}
+	if err := json.Unmarshal(bytes, def); err != nil {
+		return nil, err
+	}
+	if _, err := NewDeclared(def); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// NewDeclared makes the script for a VariantDefinition
+func NewDeclared(def *VariantDefinition) (*Declared, error) {
+	if def.Name == "" {
+		return nil, errors.New("A variant needs a Name")
+	}
+	self := &Declared{
+		ScriptBase: ScriptBase{
+			wikipedia:  def.Wikipedia,
+			cardColors: def.CardColors,
+			packs:      def.Packs,
+			suits:      def.Suits,
+			jokers:     def.Jokers,
+		},
+		def: def,
+	}
+	var ok bool
+	if self.tabCompareFunc, ok = compareFuncNamed(def.Tableau, "DownAltColor"); !ok {
+		return nil, fmt.Errorf("%s: unknown Tableau '%s'", def.Name, def.Tableau)
+	}
+	switch def.TableauMove {
+	case "":
+		self.tabMoveCompareFunc = self.tabCompareFunc
+	case "Any":
+		self.tabMoveCompareFunc = nil
+	default:
+		if self.tabMoveCompareFunc, ok = pairCompareFunc(def.TableauMove); !ok {
+			return nil, fmt.Errorf("%s: unknown TableauMove '%s'", def.Name, def.TableauMove)
+		}
+	}
+	if self.foundCompareFunc, ok = compareFuncNamed(def.Foundation, "UpSuit"); !ok {
+		return nil, fmt.Errorf("%s: unknown Foundation '%s'", def.Name, def.Foundation)
+	}
+	if strings.HasSuffix(def.Foundation, "Wild") {
+		return nil, fmt.Errorf("%s: jokers cannot go on a Foundation, so it cannot be '%s'", def.Name, def.Foundation)
+	}
+
+	var stocks, wastes, homes int
+	for _, pd := range def.Piles {
+		if _, ok := fanTypes[pd.Fan]; !ok {
+			return nil, fmt.Errorf("%s: unknown Fan '%s'", def.Name, pd.Fan)
+		}
+		if _, ok := moveTypes[pd.Move]; !ok {
+			return nil, fmt.Errorf("%s: unknown Move '%s'", def.Name, pd.Move)
+		}
+		if pd.Count < 0 {
+			return nil, fmt.Errorf("%s: a %s cannot have a Count of %d", def.Name, pd.Type, pd.Count)
+		}
+		for _, pattern := range pd.Deal {
+			for _, r := range pattern {
+				if r != 'd' && r != 'u' {
+					return nil, fmt.Errorf("%s: deal '%s' should only contain d and u", def.Name, pattern)
+				}
+			}
+		}
+		switch pd.Type {
+		case "Stock":
+			stocks += pd.count()
+			if len(pd.Deal) > 0 {
+				return nil, fmt.Errorf("%s: cannot deal to the Stock", def.Name)
+			}
+		case "Waste":
+			wastes += pd.count()
+		case "Foundation", "Discard":
+			homes += pd.count()
+		case "Cell", "Reserve", "Tableau":
+		default:
+			return nil, fmt.Errorf("%s: unknown pile Type '%s'", def.Name, pd.Type)
+		}
+	}
+	if stocks > 1 || wastes > 1 {
+		return nil, fmt.Errorf("%s: can only have one Stock and one Waste", def.Name)
+	}
+	if homes == 0 {
+		return nil, fmt.Errorf("%s: needs a Foundation or Discard", def.Name)
+	}
+	return self, nil
+}
+
+// compareFuncNamed returns the function with that name, or the one named by fallback if name is empty
+func compareFuncNamed(name string, fallback string) (CardPairCompareFunc, bool) {
+	if name == "" {
+		name = fallback
+	}
+	return pairCompareFunc(name)
+}
+
+// pairCompareFunc returns the function with that name; a name ending in Wild, eg "DownAltColorWild",
+// gives the function without it, wrapped so that jokers are wild
+func pairCompareFunc(name string) (CardPairCompareFunc, bool) {
+	if strings.HasSuffix(name, "Wild") {
+		fn, ok := pairCompareFuncs[strings.TrimSuffix(name, "Wild")]
+		if !ok {
+			return nil, false
+		}
+		return Wild(fn), true
+	}
+	fn, ok := pairCompareFuncs[name]
+	return fn, ok
+}
+
+func (pd PileDefinition) count() int {
+	if pd.Count == 0 {
+		return 1
+	}
+	return pd.Count
+}
+
+// slots returns the slot of each pile
+func (pd PileDefinition) slots() []image.Point {
+	var step image.Point = image.Point{pd.Step[0], pd.Step[1]}
+	if step == (image.Point{}) {
+		step = image.Point{1, 0}
+	}
+	var slots []image.Point = make([]image.Point, 0, pd.count())
+	var slot image.Point = image.Point{pd.Slot[0], pd.Slot[1]}
+	for i := 0; i < pd.count(); i++ {
+		slots = append(slots, slot)
+		slot = slot.Add(step)
+	}
+	return slots
+}
+
+// AddVariant makes a variant read from a file available to play, alongside the built in ones
+func AddVariant(def *VariantDefinition) error {
+	script, err := NewDeclared(def)
+	if err != nil {
+		return err
+	}
+	return addVariant(def.Name, def.Groups, script)
+}
+
+func (self *Declared) BuildPiles() {
+	// the stock is made first, because it makes the cards
+	var stock *Pile
+	for _, pd := range self.def.Piles {
+		if pd.Type == "Stock" {
+			stock = NewStock(self.baize, pd.slots()[0], fanTypes[pd.Fan], self.Packs(), self.Suits(), nil, self.Jokers())
+		}
+	}
+	if stock == nil {
+		stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, self.Packs(), self.Suits(), nil, self.Jokers())
+	}
+	self.stock = stock
+
+	self.waste = nil
+	self.cells = []*Pile{}
+	self.discards = []*Pile{}
+	self.foundations = []*Pile{}
+	self.reserves = []*Pile{}
+	self.tableaux = []*Pile{}
+	self.dealt = nil
+	for _, pd := range self.def.Piles {
+		for i, slot := range pd.slots() {
+			var pile *Pile
+			switch pd.Type {
+			case "Stock":
+				continue
+			case "Waste":
+				pile = NewWaste(self.baize, slot, fanTypes[pd.Fan])
+				self.waste = pile
+			case "Cell":
+				pile = NewCell(self.baize, slot)
+				self.cells = append(self.cells, pile)
+			case "Reserve":
+				pile = NewReserve(self.baize, slot, fanTypes[pd.Fan])
+				self.reserves = append(self.reserves, pile)
+			case "Foundation":
+				pile = NewFoundation(self.baize, slot)
+				self.foundations = append(self.foundations, pile)
+			case "Tableau":
+				pile = NewTableau(self.baize, slot, fanTypes[pd.Fan], moveTypes[pd.Move])
+				self.tableaux = append(self.tableaux, pile)
+			case "Discard":
+				pile = NewDiscard(self.baize, slot, fanTypes[pd.Fan])
+				self.discards = append(self.discards, pile)
+			}
+			if pd.Label != "" {
+				pile.SetLabel(pd.Label)
+			}
+			if len(pd.Deal) > 0 {
+				var pattern string = pd.Deal[len(pd.Deal)-1]
+				if i < len(pd.Deal) {
+					pattern = pd.Deal[i]
+				}
+				self.dealt = append(self.dealt, dealPattern{pile: pile, pattern: pattern})
+			}
+		}
+	}
+}
+
+func (self *Declared) StartGame() {
+	for _, d := range self.dealt {
+		for _, r := range d.pattern {
+			card := MoveCard(self.stock, d.pile)
+			if card == nil {
+				log.Print("No card")
+				break
+			}
+			if r == 'd' {
+				card.FlipDown()
+			}
+		}
+	}
+	self.baize.SetRecycles(self.def.Recycles)
+}
+
+func (self *Declared) TailMoveError(tail []*Card) (bool, error) {
+	var pile *Pile = tail[0].Owner()
+	switch pile.vtable.(type) {
+	case *Tableau:
+		if self.tabMoveCompareFunc != nil {
+			ok, err := TailConformant(tail, self.tabMoveCompareFunc)
+			if !ok {
+				return ok, err
+			}
+		}
+	}
+	return true, nil
+}
+
+func (self *Declared) TailAppendError(dst *Pile, tail []*Card) (bool, error) {
+	switch dst.vtable.(type) {
+	case *Foundation:
+		if dst.Empty() {
+			return Compare_Empty(dst, tail[0])
+		} else {
+			return self.foundCompareFunc(CardPair{dst.Peek(), tail[0]})
+		}
+	case *Tableau:
+		if dst.Empty() {
+			return Compare_Empty(dst, tail[0])
+		} else {
+			return appendConformant(dst, tail[0], self.tabCompareFunc)
+		}
+	case *Discard:
+		if tail[0].Ordinal() != 13 {
+			return false, errors.New("Can only discard starting from a King")
+		}
+		return TailConformant(tail, self.tabCompareFunc)
+	}
+	return true, nil
+}
+
+func (self *Declared) UnsortedPairs(pile *Pile) int {
+	return UnsortedPairs(pile, self.tabCompareFunc)
+}
+
+func (self *Declared) TailTapped(tail []*Card) {
+	var pile *Pile = tail[0].Owner()
+	if pile == self.stock && len(tail) == 1 {
+		if self.waste != nil {
+			var draw int = self.def.Draw
+			if draw == 0 {
+				draw = 1
+			}
+			for i := 0; i < draw; i++ {
+				MoveCard(self.stock, self.waste)
+			}
+		} else {
+			for _, tab := range self.tableaux {
+				MoveCard(self.stock, tab)
+			}
+		}
+	} else {
+		pile.vtable.TailTapped(tail)
+	}
+}
+
+func (self *Declared) PileTapped(pile *Pile) {
+	if pile == self.stock && self.waste != nil {
+		RecycleWasteToStock(self.waste, self.stock)
+	}
+}
+
+// Complete counts the cards on discards as well as foundations
+func (self *Declared) Complete() bool {
+	var n = 0
+	for _, f := range self.foundations {
+		n += len(f.cards)
+	}
+	for _, d := range self.discards {
+		n += len(d.cards)
+	}
+	return n == self.baize.homeCount()
+}
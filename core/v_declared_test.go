@@ -0,0 +1,240 @@
+package core
+
+import (
+	"testing"
+
+	"oddstream.games/gosol/cardid"
+)
+
+const littleKlondike = `{
+	"Name": "Little Klondike",
+	"Groups": ["> Klondikes"],
+	"Recycles": 2,
+	"Draw": 3,
+	"Piles": [
+		{"Type": "Stock", "Slot": [0, 0]},
+		{"Type": "Waste", "Slot": [1, 0], "Fan": "Right3"},
+		{"Type": "Foundation", "Slot": [3, 0], "Count": 4, "Label": "A"},
+		{"Type": "Tableau", "Slot": [0, 1], "Count": 7, "Fan": "Down", "Label": "K",
+			"Deal": ["u", "du", "ddu", "dddu", "ddddu", "dddddu", "ddddddu"]}
+	]
+}`
+
+// removeVariantWhenDone takes a variant added by a test out of Variants and VariantGroups again,
+// so the tests that go through every variant do not depend on which tests ran first
+func removeVariantWhenDone(t *testing.T, name string) {
+	t.Cleanup(func() {
+		delete(Variants, name)
+		for group, names := range VariantGroups {
+			var kept []string
+			for _, n := range names {
+				if n != name {
+					kept = append(kept, n)
+				}
+			}
+			if len(kept) == 0 {
+				delete(VariantGroups, group)
+			} else {
+				VariantGroups[group] = kept
+			}
+		}
+	})
+}
+
+// TestDeclaredVariant plays a variant read from JSON
+func TestDeclaredVariant(t *testing.T) {
+	def, err := ParseVariant([]byte(littleKlondike))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := AddVariant(def); err != nil {
+		t.Fatal(err)
+	}
+	removeVariantWhenDone(t, def.Name)
+	if err := AddVariant(def); err == nil {
+		t.Error("added the same variant twice")
+	}
+	var found bool
+	for _, name := range VariantGroups["> Custom"] {
+		found = found || name == def.Name
+	}
+	if !found {
+		t.Error("variant is not in the > Custom group")
+	}
+
+	b := NewBaize(def.Name, nil)
+	b.StartFreshGame(12345)
+	script := b.Script()
+	if len(script.Foundations()) != 4 || len(script.Tableaux()) != 7 || script.Waste() == nil {
+		t.Fatalf("built %d foundations and %d tableaux", len(script.Foundations()), len(script.Tableaux()))
+	}
+	if n := countCards(b); n != 52 || b.CardCount() != 52 {
+		t.Errorf("dealt %d of %d cards", n, b.CardCount())
+	}
+	for i, tab := range script.Tableaux() {
+		if tab.Len() != i+1 {
+			t.Errorf("tableau %d has %d cards", i, tab.Len())
+		}
+		for j, c := range tab.Cards() {
+			if c.Prone() != (j < i) {
+				t.Errorf("tableau %d card %d prone is %v", i, j, c.Prone())
+			}
+		}
+	}
+	if b.Recycles() != 2 {
+		t.Errorf("%d recycles", b.Recycles())
+	}
+	b.TailTapped([]*Card{script.Stock().Peek()})
+	if script.Waste().Len() != 3 {
+		t.Errorf("drew %d cards", script.Waste().Len())
+	}
+}
+
+// TestBadVariants checks that mistakes in a variant file are reported
+func TestBadVariants(t *testing.T) {
+	for _, bad := range []string{
+		`{"Piles": [{"Type": "Foundation"}]}`,
+		`{"Name": "No homes", "Piles": [{"Type": "Tableau"}]}`,
+		`{"Name": "Bad type", "Piles": [{"Type": "Foundation"}, {"Type": "Tablo"}]}`,
+		`{"Name": "Bad fan", "Piles": [{"Type": "Foundation", "Fan": "Up"}]}`,
+		`{"Name": "Bad compare", "Tableau": "Sideways", "Piles": [{"Type": "Foundation"}]}`,
+		`{"Name": "Wild foundation", "Foundation": "UpSuitWild", "Piles": [{"Type": "Foundation"}]}`,
+		`{"Name": "Bad deal", "Piles": [{"Type": "Foundation"}, {"Type": "Tableau", "Deal": ["uux"]}]}`,
+		`{"Name": "Two stocks", "Piles": [{"Type": "Foundation"}, {"Type": "Stock", "Count": 2}]}`,
+		`{"Name": "Not JSON"`,
+	} {
+		if _, err := ParseVariant([]byte(bad)); err == nil {
+			t.Errorf("%s was accepted", bad)
+		}
+	}
+}
+
+// TestJokers deals a variant with wild jokers, and puts a joker between two cards that do not build on each other
+func TestJokers(t *testing.T) {
+	def, err := ParseVariant([]byte(`{
+	"Name": "Joker Klondike",
+	"Jokers": 2,
+	"Tableau": "DownAltColorWild",
+	"Piles": [
+		{"Type": "Stock", "Slot": [0, 0]},
+		{"Type": "Foundation", "Slot": [3, 0], "Count": 4, "Label": "A"},
+		{"Type": "Tableau", "Slot": [0, 1], "Count": 7, "Fan": "Down", "Label": "K",
+			"Deal": ["u", "du", "ddu", "dddu", "ddddu", "dddddu", "ddddddu"]}
+	]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := AddVariant(def); err != nil {
+		t.Fatal(err)
+	}
+	removeVariantWhenDone(t, def.Name)
+	b := NewBaize(def.Name, nil)
+	b.StartFreshGame(12345)
+	if n := countCards(b); n != 54 || b.CardCount() != 54 {
+		t.Errorf("dealt %d of %d cards", n, b.CardCount())
+	}
+	var jokers int
+	for _, p := range b.Piles() {
+		for _, c := range p.Cards() {
+			if c.Joker() {
+				jokers++
+			}
+		}
+	}
+	if jokers != 2 {
+		t.Errorf("dealt %d jokers", jokers)
+	}
+
+	var joker Card = NewCard(0, cardid.NOSUIT, 0)
+	var six, four Card = NewCard(0, cardid.CLUB, 6), NewCard(0, cardid.HEART, 4)
+	var wild CardPairCompareFunc = Wild(CardPair.Compare_DownAltColor)
+	if ok, _ := wild(CardPair{&six, &joker}); !ok {
+		t.Error("joker not accepted on a six")
+	}
+	if ok, _ := wild(CardPair{&joker, &four}); !ok {
+		t.Error("four not accepted on a joker")
+	}
+	if ok, _ := wild(CardPair{&six, &four}); ok {
+		t.Error("four accepted on a six")
+	}
+
+	// the cards either side of jokers must agree on what the jokers stand for
+	var nine, fourClubs, threeHearts Card = NewCard(0, cardid.HEART, 9), NewCard(0, cardid.CLUB, 4), NewCard(0, cardid.HEART, 3)
+	var joker2 Card = NewCard(0, cardid.NOSUIT, 0)
+	if ok, _ := TailConformant([]*Card{&six, &joker, &nine}, wild); ok {
+		t.Error("6C JK 9H is conformant")
+	}
+	if ok, err := TailConformant([]*Card{&six, &joker, &fourClubs}, wild); !ok {
+		t.Error("6C JK 4C is not conformant:", err)
+	}
+	if ok, err := TailConformant([]*Card{&six, &joker, &joker2, &threeHearts}, wild); !ok {
+		t.Error("6C JK JK 3H is not conformant:", err)
+	}
+	var tab *Pile = b.Script().Tableaux()[0]
+	tab.cards = []*Card{&six, &joker}
+	if ok, _ := appendConformant(tab, &nine, wild); ok {
+		t.Error("9H accepted on 6C JK")
+	}
+	if ok, err := appendConformant(tab, &fourClubs, wild); !ok {
+		t.Error("4C not accepted on 6C JK:", err)
+	}
+	if n := UnsortedPairs(tab, wild); n != 0 {
+		t.Errorf("6C JK has %d unsorted pairs", n)
+	}
+	tab.cards = append(tab.cards, &nine)
+	if n := UnsortedPairs(tab, wild); n != 1 {
+		t.Errorf("6C JK 9H has %d unsorted pairs", n)
+	}
+	if _, ok := pairCompareFunc("SidewaysWild"); ok {
+		t.Error("SidewaysWild accepted")
+	}
+}
+
+// TestJokerGameCompletes plays a one suit game with a joker to the end, which leaves the joker behind
+func TestJokerGameCompletes(t *testing.T) {
+	def, err := ParseVariant([]byte(`{
+	"Name": "Joker Patience",
+	"Suits": 1,
+	"Jokers": 1,
+	"Tableau": "DownWild",
+	"Piles": [
+		{"Type": "Stock", "Slot": [-5, -5]},
+		{"Type": "Foundation", "Slot": [0, 0], "Label": "A"},
+		{"Type": "Tableau", "Slot": [0, 1], "Count": 14, "Deal": ["u"]}
+	]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := AddVariant(def); err != nil {
+		t.Fatal(err)
+	}
+	removeVariantWhenDone(t, def.Name)
+	b := NewBaize(def.Name, nil)
+	b.StartFreshGame(12345)
+	var foundation *Pile = b.Script().Foundations()[0]
+	for _, p := range b.Script().Tableaux() {
+		if c := p.Peek(); c != nil && c.Joker() {
+			if err := b.MoveTailTo([]*Card{c}, foundation); err == nil {
+				t.Error("joker moved to a Foundation")
+			}
+		}
+	}
+	for ord := 1; ord <= 13; ord++ {
+		for _, p := range b.Script().Tableaux() {
+			if c := p.Peek(); c != nil && c.Ordinal() == ord {
+				if err := b.MoveTailTo([]*Card{c}, foundation); err != nil {
+					t.Fatal(err)
+				}
+				b.AfterUserMove()
+			}
+		}
+	}
+	if foundation.Len() != 13 {
+		t.Errorf("%d cards on the Foundation", foundation.Len())
+	}
+	if !b.Complete() {
+		t.Error("game not complete with only the joker left")
+	}
+}
@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 Receiver name will be anything I like, thank you
@@ -25,23 +25,23 @@ func (self *Freecell) BuildPiles() {
 		self.tabCompareFunc = CardPair.Compare_DownAltColor
 	}
 
-	self.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
 
 	self.cells = []*Pile{}
 	for x := 0; x < 4; x++ {
-		self.cells = append(self.cells, NewCell(image.Point{x, 0}))
+		self.cells = append(self.cells, NewCell(self.baize, image.Point{x, 0}))
 	}
 
 	self.foundations = []*Pile{}
 	for x := 4; x < 8; x++ {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
 
 	self.tableaux = []*Pile{}
 	for x := 0; x < 8; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ONE_PLUS)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ONE_PLUS)
 		self.tableaux = append(self.tableaux, t)
 	}
 }
@@ -122,6 +122,12 @@ func (self *Freecell) UnsortedPairs(pile *Pile) int {
 	return UnsortedPairs(pile, self.tabCompareFunc)
 }
 
+// MicrosoftDeals is true for Freecell, Baker's Game and Blind Freecell;
+// Freecell Easy starts with the aces on the foundations, so has a different layout
+func (self *Freecell) MicrosoftDeals() bool {
+	return !self.easy
+}
+
 func (*Freecell) TailTapped(tail []*Card) {
 	tail[0].Owner().vtable.TailTapped(tail)
 }
@@ -0,0 +1,568 @@
+package core
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+//lint:file-ignore ST1006 I'll call the receiver anything I like, thank you
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Lua is the script for a variant written in Lua, so it can be played without recompiling.
+//
+// The script sets the globals Name (required), Groups, Wikipedia, CardColors, Packs, Suits and Jokers (per pack),
+// and defines the functions BuildPiles, StartGame and TailAppendError (required),
+// and TailMoveError, UnsortedPairs, AfterMove, TailTapped and PileTapped (optional).
+// They are called just like the methods of Scripter; errors are returned as false, "message".
+//
+// Piles are made with NewStock, NewWaste, NewCell, NewReserve, NewFoundation, NewTableau and NewDiscard,
+// and found again with Stock, Waste, Cells, Reserves, Foundations, Tableaux and Discards.
+// Cards are moved with MoveCard, MoveTail and RecycleWasteToStock, and compared with
+// Compare, CompareEmpty, TailConformant and CountUnsortedPairs, which take the names of the
+// CardPair.Compare_ functions without the prefix, eg "DownAltColor", with Wild after it
+// for jokers to be wild, eg "DownAltColorWild", except on a Foundation, where jokers never go.
+type Lua struct {
+	ScriptBase
+	name, source string
+	groups       []string
+	L            *lua.LState // made afresh by BuildPiles for each Baize
+	piles        map[*Pile]*lua.LUserData
+	cards        map[*Card]*lua.LUserData // emptied by forgetCards, as the cards come and go
+}
+
+// luaTimeout is how long a script is given to run a function, so one that never returns cannot hang the game
+var luaTimeout time.Duration = 2 * time.Second
+
+const (
+	luaPileType = "pile"
+	luaCardType = "card"
+)
+
+// NewLua runs a variant script once, to check it and find out what the variant is called
+func NewLua(source string) (*Lua, error) {
+	self := &Lua{source: source}
+	L, err := self.newState()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// the prototype in Variants does not keep an interpreter
+		L.Close()
+		self.L, self.piles, self.cards = nil, nil, nil
+	}()
+
+	if name, ok := L.GetGlobal("Name").(lua.LString); ok && name != "" {
+		self.name = string(name)
+	} else {
+		return nil, errors.New("A variant script needs a Name")
+	}
+	for _, fn := range []string{"BuildPiles", "StartGame", "TailAppendError"} {
+		if _, ok := L.GetGlobal(fn).(*lua.LFunction); !ok {
+			return nil, fmt.Errorf("%s: needs a function called %s", self.name, fn)
+		}
+	}
+	if groups, ok := L.GetGlobal("Groups").(*lua.LTable); ok {
+		groups.ForEach(func(_, v lua.LValue) {
+			self.groups = append(self.groups, v.String())
+		})
+	}
+	if s, ok := L.GetGlobal("Wikipedia").(lua.LString); ok {
+		self.wikipedia = string(s)
+	}
+	if n, ok := L.GetGlobal("CardColors").(lua.LNumber); ok {
+		self.cardColors = int(n)
+	}
+	if n, ok := L.GetGlobal("Packs").(lua.LNumber); ok {
+		self.packs = int(n)
+	}
+	if n, ok := L.GetGlobal("Suits").(lua.LNumber); ok {
+		self.suits = int(n)
+	}
+	if n, ok := L.GetGlobal("Jokers").(lua.LNumber); ok {
+		self.jokers = int(n)
+	}
+	return self, nil
+}
+
+// AddLua makes a variant written in Lua available to play, alongside the built in ones
+func AddLua(source string) error {
+	script, err := NewLua(source)
+	if err != nil {
+		return err
+	}
+	return addVariant(script.name, script.groups, script)
+}
+
+// newState makes a Lua interpreter that can only get at the cards, and runs the script in it
+func (self *Lua) newState() (*lua.LState, error) {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	self.L = L
+	self.piles = make(map[*Pile]*lua.LUserData)
+	self.cards = make(map[*Card]*lua.LUserData)
+	for _, lib := range []struct {
+		name string
+		fn   lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.fn))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	// no reading other files
+	for _, name := range []string{"dofile", "loadfile", "require"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	pileMethods := map[string]lua.LGFunction{
+		"Category": func(L *lua.LState) int {
+			L.Push(lua.LString(self.checkPile(L, 1).Category()))
+			return 1
+		},
+		"Label": func(L *lua.LState) int {
+			L.Push(lua.LString(self.checkPile(L, 1).Label()))
+			return 1
+		},
+		"SetLabel": func(L *lua.LState) int {
+			self.checkPile(L, 1).SetLabel(L.CheckString(2))
+			return 0
+		},
+		"Len": func(L *lua.LState) int {
+			L.Push(lua.LNumber(self.checkPile(L, 1).Len()))
+			return 1
+		},
+		"Empty": func(L *lua.LState) int {
+			L.Push(lua.LBool(self.checkPile(L, 1).Empty()))
+			return 1
+		},
+		"Peek": func(L *lua.LState) int {
+			L.Push(self.card(self.checkPile(L, 1).Peek()))
+			return 1
+		},
+		"Get": func(L *lua.LState) int {
+			pile := self.checkPile(L, 1)
+			i := L.CheckInt(2)
+			if i < 1 || i > pile.Len() {
+				L.Push(lua.LNil)
+			} else {
+				L.Push(self.card(pile.Get(i - 1)))
+			}
+			return 1
+		},
+		"Cards": func(L *lua.LState) int {
+			L.Push(self.cardTable(self.checkPile(L, 1).Cards()))
+			return 1
+		},
+		"TailTapped": func(L *lua.LState) int {
+			self.checkPile(L, 1).vtable.TailTapped(self.checkTail(L, 2))
+			return 0
+		},
+	}
+	mt := L.NewTypeMetatable(luaPileType)
+	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), pileMethods))
+
+	cardMethods := map[string]lua.LGFunction{
+		"Ordinal": func(L *lua.LState) int {
+			L.Push(lua.LNumber(self.checkCard(L, 1).Ordinal()))
+			return 1
+		},
+		"Suit": func(L *lua.LState) int {
+			L.Push(lua.LNumber(self.checkCard(L, 1).Suit()))
+			return 1
+		},
+		"Black": func(L *lua.LState) int {
+			L.Push(lua.LBool(self.checkCard(L, 1).Black()))
+			return 1
+		},
+		"Joker": func(L *lua.LState) int {
+			L.Push(lua.LBool(self.checkCard(L, 1).Joker()))
+			return 1
+		},
+		"Prone": func(L *lua.LState) int {
+			L.Push(lua.LBool(self.checkCard(L, 1).Prone()))
+			return 1
+		},
+		"FlipUp": func(L *lua.LState) int {
+			self.checkCard(L, 1).FlipUp()
+			return 0
+		},
+		"FlipDown": func(L *lua.LState) int {
+			self.checkCard(L, 1).FlipDown()
+			return 0
+		},
+		"Owner": func(L *lua.LState) int {
+			L.Push(self.pile(self.checkCard(L, 1).Owner()))
+			return 1
+		},
+	}
+	mt = L.NewTypeMetatable(luaCardType)
+	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), cardMethods))
+	L.SetField(mt, "__tostring", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LString(self.checkCard(L, 1).String()))
+		return 1
+	}))
+
+	// newPile makes a function that makes a pile at slot x, y, with an optional fan type
+	newPile := func(fn func(image.Point, FanType) *Pile) lua.LGFunction {
+		return func(L *lua.LState) int {
+			if self.baize == nil {
+				L.RaiseError("piles can only be made in BuildPiles")
+			}
+			fan, ok := fanTypes[L.OptString(3, "")]
+			if !ok {
+				L.ArgError(3, "unknown fan type")
+			}
+			L.Push(self.pile(fn(image.Point{L.CheckInt(1), L.CheckInt(2)}, fan)))
+			return 1
+		}
+	}
+	piles := func(fn func() []*Pile) lua.LGFunction {
+		return func(L *lua.LState) int {
+			tbl := L.NewTable()
+			for _, p := range fn() {
+				tbl.Append(self.pile(p))
+			}
+			L.Push(tbl)
+			return 1
+		}
+	}
+	globals := map[string]lua.LGFunction{
+		"NewStock": newPile(func(slot image.Point, fan FanType) *Pile {
+			self.stock = NewStock(self.baize, slot, fan, self.Packs(), self.Suits(), nil, self.Jokers())
+			return self.stock
+		}),
+		"NewWaste": newPile(func(slot image.Point, fan FanType) *Pile {
+			self.waste = NewWaste(self.baize, slot, fan)
+			return self.waste
+		}),
+		"NewCell": newPile(func(slot image.Point, _ FanType) *Pile {
+			p := NewCell(self.baize, slot)
+			self.cells = append(self.cells, p)
+			return p
+		}),
+		"NewReserve": newPile(func(slot image.Point, fan FanType) *Pile {
+			p := NewReserve(self.baize, slot, fan)
+			self.reserves = append(self.reserves, p)
+			return p
+		}),
+		"NewFoundation": newPile(func(slot image.Point, _ FanType) *Pile {
+			p := NewFoundation(self.baize, slot)
+			self.foundations = append(self.foundations, p)
+			return p
+		}),
+		"NewDiscard": newPile(func(slot image.Point, fan FanType) *Pile {
+			p := NewDiscard(self.baize, slot, fan)
+			self.discards = append(self.discards, p)
+			return p
+		}),
+		"NewTableau": func(L *lua.LState) int {
+			if self.baize == nil {
+				L.RaiseError("piles can only be made in BuildPiles")
+			}
+			fan, ok := fanTypes[L.OptString(3, "")]
+			if !ok {
+				L.ArgError(3, "unknown fan type")
+			}
+			move, ok := moveTypes[L.OptString(4, "")]
+			if !ok {
+				L.ArgError(4, "unknown move type")
+			}
+			p := NewTableau(self.baize, image.Point{L.CheckInt(1), L.CheckInt(2)}, fan, move)
+			self.tableaux = append(self.tableaux, p)
+			L.Push(self.pile(p))
+			return 1
+		},
+		"Stock": func(L *lua.LState) int {
+			L.Push(self.pile(self.stock))
+			return 1
+		},
+		"Waste": func(L *lua.LState) int {
+			L.Push(self.pile(self.waste))
+			return 1
+		},
+		"Cells":       piles(func() []*Pile { return self.cells }),
+		"Discards":    piles(func() []*Pile { return self.discards }),
+		"Foundations": piles(func() []*Pile { return self.foundations }),
+		"Reserves":    piles(func() []*Pile { return self.reserves }),
+		"Tableaux":    piles(func() []*Pile { return self.tableaux }),
+		"MoveCard": func(L *lua.LState) int {
+			L.Push(self.card(MoveCard(self.checkPile(L, 1), self.checkPile(L, 2))))
+			return 1
+		},
+		"MoveTail": func(L *lua.LState) int {
+			MoveTail(self.checkCard(L, 1), self.checkPile(L, 2))
+			return 0
+		},
+		"RecycleWasteToStock": func(L *lua.LState) int {
+			RecycleWasteToStock(self.checkPile(L, 1), self.checkPile(L, 2))
+			return 0
+		},
+		"Recycles": func(L *lua.LState) int {
+			L.Push(lua.LNumber(self.baize.Recycles()))
+			return 1
+		},
+		"SetRecycles": func(L *lua.LState) int {
+			self.baize.SetRecycles(L.CheckInt(1))
+			return 0
+		},
+		"Toast": func(L *lua.LState) int {
+			self.baize.toastInfo(L.CheckString(1))
+			return 0
+		},
+		"Compare": func(L *lua.LState) int {
+			c1, c2 := self.checkCard(L, 1), self.checkCard(L, 2)
+			ok, err := checkCompareFunc(L, 3, c1.Owner())(CardPair{c1, c2})
+			return pushResult(L, ok, err)
+		},
+		"CompareEmpty": func(L *lua.LState) int {
+			ok, err := Compare_Empty(self.checkPile(L, 1), self.checkCard(L, 2))
+			return pushResult(L, ok, err)
+		},
+		"TailConformant": func(L *lua.LState) int {
+			tail := self.checkTail(L, 1)
+			ok, err := TailConformant(tail, checkCompareFunc(L, 2, tail[0].Owner()))
+			return pushResult(L, ok, err)
+		},
+		"CountUnsortedPairs": func(L *lua.LState) int {
+			p := self.checkPile(L, 1)
+			L.Push(lua.LNumber(UnsortedPairs(p, checkCompareFunc(L, 2, p))))
+			return 1
+		},
+	}
+	for name, fn := range globals {
+		L.SetGlobal(name, L.NewFunction(fn))
+	}
+
+	chunk, err := L.Load(strings.NewReader(self.source), "variant")
+	if err != nil {
+		L.Close()
+		return nil, err
+	}
+	L.Push(chunk)
+	if err := limited(L, func() error { return L.PCall(0, 0, nil) }); err != nil {
+		L.Close()
+		return nil, err
+	}
+	return L, nil
+}
+
+// pile returns the Lua value for a pile, always the same one, so piles can be compared with ==
+func (self *Lua) pile(p *Pile) lua.LValue {
+	if p == nil {
+		return lua.LNil
+	}
+	if ud, ok := self.piles[p]; ok {
+		return ud
+	}
+	ud := self.L.NewUserData()
+	ud.Value = p
+	self.L.SetMetatable(ud, self.L.GetTypeMetatable(luaPileType))
+	self.piles[p] = ud
+	return ud
+}
+
+// card returns the Lua value for a card, always the same one, so cards can be compared with ==
+func (self *Lua) card(c *Card) lua.LValue {
+	if c == nil {
+		return lua.LNil
+	}
+	if ud, ok := self.cards[c]; ok {
+		return ud
+	}
+	ud := self.L.NewUserData()
+	ud.Value = c
+	self.L.SetMetatable(ud, self.L.GetTypeMetatable(luaCardType))
+	self.cards[c] = ud
+	return ud
+}
+
+// forgetCards lets go of the Lua values for cards that have been replaced
+func (self *Lua) forgetCards() {
+	if self.cards != nil {
+		self.cards = make(map[*Card]*lua.LUserData)
+	}
+}
+
+func (self *Lua) cardTable(cards []*Card) *lua.LTable {
+	tbl := self.L.NewTable()
+	for _, c := range cards {
+		tbl.Append(self.card(c))
+	}
+	return tbl
+}
+
+func (self *Lua) checkPile(L *lua.LState, n int) *Pile {
+	if p, ok := L.CheckUserData(n).Value.(*Pile); ok {
+		return p
+	}
+	L.ArgError(n, "pile expected")
+	return nil
+}
+
+func (self *Lua) checkCard(L *lua.LState, n int) *Card {
+	if c, ok := L.CheckUserData(n).Value.(*Card); ok {
+		return c
+	}
+	L.ArgError(n, "card expected")
+	return nil
+}
+
+func (self *Lua) checkTail(L *lua.LState, n int) []*Card {
+	var tail []*Card
+	L.CheckTable(n).ForEach(func(_, v lua.LValue) {
+		if ud, ok := v.(*lua.LUserData); ok {
+			if c, ok := ud.Value.(*Card); ok {
+				tail = append(tail, c)
+				return
+			}
+		}
+		L.ArgError(n, "table of cards expected")
+	})
+	if len(tail) == 0 {
+		L.ArgError(n, "empty tail")
+	}
+	return tail
+}
+
+// checkCompareFunc returns the compare named by the n'th argument, which is to be used on cards in pile p;
+// jokers cannot go on a Foundation, so it cannot have a Wild compare
+func checkCompareFunc(L *lua.LState, n int, p *Pile) CardPairCompareFunc {
+	var name string = L.CheckString(n)
+	fn, ok := pairCompareFunc(name)
+	if !ok {
+		L.ArgError(n, "unknown compare function")
+	}
+	if p != nil && p.Category() == "Foundation" && strings.HasSuffix(name, "Wild") {
+		L.ArgError(n, "a Foundation cannot have a Wild compare")
+	}
+	return fn
+}
+
+// pushResult gives Lua the result of a compare as true, or false, "message"
+func pushResult(L *lua.LState, ok bool, err error) int {
+	L.Push(lua.LBool(ok))
+	if err != nil {
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+	return 1
+}
+
+// defined returns true if the script has a function called name
+func (self *Lua) defined(name string) bool {
+	_, ok := self.L.GetGlobal(name).(*lua.LFunction)
+	return ok
+}
+
+// call calls a function in the script, and returns what it returns
+func (self *Lua) call(name string, nret int, args ...lua.LValue) ([]lua.LValue, error) {
+	fn, ok := self.L.GetGlobal(name).(*lua.LFunction)
+	if !ok {
+		return nil, fmt.Errorf("%s: no function called %s", self.name, name)
+	}
+	if err := limited(self.L, func() error { return self.L.CallByParam(lua.P{Fn: fn, NRet: nret, Protect: true}, args...) }); err != nil {
+		log.Println(self.name, err)
+		self.baize.toastError(fmt.Sprintf("%s: %s", self.name, err))
+		return nil, err
+	}
+	var results []lua.LValue = make([]lua.LValue, nret)
+	for i := range results {
+		results[i] = self.L.Get(i - nret)
+	}
+	self.L.Pop(nret)
+	return results, nil
+}
+
+// limited runs fn, stopping the script if it takes longer than luaTimeout
+func limited(L *lua.LState, fn func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), luaTimeout)
+	defer cancel()
+	L.SetContext(ctx)
+	defer L.RemoveContext()
+	return fn()
+}
+
+// callResult calls a function in the script that returns true, or false, "message"
+func (self *Lua) callResult(name string, args ...lua.LValue) (bool, error) {
+	results, err := self.call(name, 2, args...)
+	if err != nil {
+		return false, err
+	}
+	if lua.LVAsBool(results[0]) {
+		return true, nil
+	}
+	if results[1] == lua.LNil {
+		return false, errors.New("Cannot move those cards there")
+	}
+	return false, errors.New(results[1].String())
+}
+
+func (self *Lua) BuildPiles() {
+	// a fresh interpreter for each Baize, because it holds on to the piles
+	self.cells, self.discards, self.foundations, self.reserves, self.tableaux = nil, nil, nil, nil, nil
+	self.stock, self.waste = nil, nil
+	if _, err := self.newState(); err != nil {
+		log.Panic(self.name, err)
+	}
+	self.call("BuildPiles", 0)
+	if self.stock == nil {
+		self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, self.Packs(), self.Suits(), nil, self.Jokers())
+	}
+}
+
+func (self *Lua) StartGame() {
+	self.call("StartGame", 0)
+}
+
+func (self *Lua) AfterMove() {
+	if self.defined("AfterMove") {
+		self.call("AfterMove", 0)
+	}
+}
+
+func (self *Lua) TailMoveError(tail []*Card) (bool, error) {
+	if !self.defined("TailMoveError") {
+		return true, nil
+	}
+	return self.callResult("TailMoveError", self.cardTable(tail))
+}
+
+func (self *Lua) TailAppendError(dst *Pile, tail []*Card) (bool, error) {
+	return self.callResult("TailAppendError", self.pile(dst), self.cardTable(tail))
+}
+
+func (self *Lua) UnsortedPairs(pile *Pile) int {
+	if !self.defined("UnsortedPairs") {
+		return UnsortedPairs(pile, CardPair.Compare_DownAltColor)
+	}
+	results, err := self.call("UnsortedPairs", 1, self.pile(pile))
+	if err != nil {
+		return 0
+	}
+	n, _ := results[0].(lua.LNumber)
+	return int(n)
+}
+
+func (self *Lua) TailTapped(tail []*Card) {
+	if !self.defined("TailTapped") {
+		tail[0].Owner().vtable.TailTapped(tail)
+		return
+	}
+	self.call("TailTapped", 0, self.cardTable(tail))
+}
+
+func (self *Lua) PileTapped(pile *Pile) {
+	if self.defined("PileTapped") {
+		self.call("PileTapped", 0, self.pile(pile))
+	}
+}
@@ -0,0 +1,158 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+const luaKlondike = `
+Name = "Lua Klondike"
+Groups = {"> Klondikes"}
+
+function BuildPiles()
+	NewStock(0, 0)
+	NewWaste(1, 0, "Right3")
+	for x = 3, 6 do
+		NewFoundation(x, 0):SetLabel("A")
+	end
+	for x = 0, 6 do
+		NewTableau(x, 1, "Down", "Any"):SetLabel("K")
+	end
+end
+
+function StartGame()
+	for i, t in ipairs(Tableaux()) do
+		for n = 1, i - 1 do
+			MoveCard(Stock(), t):FlipDown()
+		end
+		MoveCard(Stock(), t)
+	end
+	SetRecycles(1)
+end
+
+function TailMoveError(tail)
+	if tail[1]:Owner():Category() == "Tableau" then
+		return TailConformant(tail, "DownAltColor")
+	end
+	return true
+end
+
+function TailAppendError(dst, tail)
+	if dst:Category() == "Foundation" then
+		if dst:Empty() then
+			return CompareEmpty(dst, tail[1])
+		end
+		return Compare(dst:Peek(), tail[1], "UpSuit")
+	elseif dst:Category() == "Tableau" then
+		if dst:Empty() then
+			return CompareEmpty(dst, tail[1])
+		end
+		return Compare(dst:Peek(), tail[1], "DownAltColor")
+	end
+	return true
+end
+
+function UnsortedPairs(pile)
+	return CountUnsortedPairs(pile, "DownAltColor")
+end
+
+function TailTapped(tail)
+	local pile = tail[1]:Owner()
+	if pile == Stock() then
+		MoveCard(Stock(), Waste())
+	else
+		pile:TailTapped(tail)
+	end
+end
+
+function PileTapped(pile)
+	if pile == Stock() then
+		RecycleWasteToStock(Waste(), Stock())
+	end
+end
+`
+
+// TestLuaVariant plays a variant written in Lua
+func TestLuaVariant(t *testing.T) {
+	if err := AddLua(luaKlondike); err != nil {
+		t.Fatal(err)
+	}
+	removeVariantWhenDone(t, "Lua Klondike")
+	b := NewBaize("Lua Klondike", nil)
+	b.StartFreshGame(12345)
+	script := b.Script()
+	if len(script.Foundations()) != 4 || len(script.Tableaux()) != 7 {
+		t.Fatalf("built %d foundations and %d tableaux", len(script.Foundations()), len(script.Tableaux()))
+	}
+	if n := countCards(b); n != 52 {
+		t.Errorf("dealt %d cards", n)
+	}
+	for i, tab := range script.Tableaux() {
+		if tab.Len() != i+1 || tab.Peek().Prone() {
+			t.Errorf("tableau %d has %d cards", i, tab.Len())
+		}
+	}
+
+	// a card that is not an ace cannot go on an empty foundation
+	for _, tab := range script.Tableaux() {
+		c := tab.Peek()
+		ok, err := script.TailAppendError(script.Foundations()[0], []*Card{c})
+		if ok != (c.Ordinal() == 1) {
+			t.Errorf("%s to an empty foundation %v %v", c, ok, err)
+		}
+	}
+
+	// the script's own UnsortedPairs can use the helper
+	for _, tab := range script.Tableaux() {
+		if n := script.UnsortedPairs(tab); n != UnsortedPairs(tab, CardPair.Compare_DownAltColor) {
+			t.Errorf("%d unsorted pairs", n)
+		}
+	}
+
+	stock := script.Stock()
+	for !stock.Empty() {
+		b.TailTapped([]*Card{stock.Peek()})
+	}
+	if script.Waste().Len() != 24 {
+		t.Errorf("waste has %d cards", script.Waste().Len())
+	}
+	b.PileTapped(stock)
+	if stock.Len() != 24 || b.Recycles() != 0 {
+		t.Errorf("stock has %d cards after recycling, %d recycles left", stock.Len(), b.Recycles())
+	}
+
+	// the script does not hold on to cards that have been replaced, eg by undo
+	for i := 0; i < 10; i++ {
+		b.SetPosition(b.Position())
+		for _, tab := range script.Tableaux() {
+			script.TailAppendError(script.Foundations()[0], []*Card{tab.Peek()})
+		}
+	}
+	if n := len(script.(*Lua).cards); n > len(script.Tableaux()) {
+		t.Errorf("script holds %d cards", n)
+	}
+
+	// a copy gets an interpreter of its own
+	cp := b.Copy()
+	if cp.Script().Stock() == stock || cp.Script().Stock().Len() != 24 {
+		t.Error("copy shares piles with the original")
+	}
+}
+
+// TestBadLua checks that mistakes in a variant script are reported
+func TestBadLua(t *testing.T) {
+	defer func(d time.Duration) { luaTimeout = d }(luaTimeout)
+	luaTimeout = 100 * time.Millisecond
+	for _, bad := range []string{
+		`function BuildPiles() end`,
+		`Name = "No functions"`,
+		`Name = "Syntax" function BuildPiles(`,
+		`Name = "Runtime" error("oops")`,
+		`Name = "No files" dofile("/etc/passwd")`,
+		`Name = "Endless" while true do end`,
+	} {
+		if _, err := NewLua(bad); err == nil {
+			t.Errorf("%s was accepted", bad)
+		}
+	}
+}
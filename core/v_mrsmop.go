@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 I'll call the receiver anything I like, thank you
@@ -16,26 +16,26 @@ type MrsMop struct {
 
 func (self *MrsMop) BuildPiles() {
 
-	self.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 2, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 2, 4, nil, 0)
 
 	self.discards = []*Pile{}
 	for x := 0; x < 4; x++ {
-		d := NewDiscard(image.Point{x, 0}, FAN_NONE)
+		d := NewDiscard(self.baize, image.Point{x, 0}, FAN_NONE)
 		self.discards = append(self.discards, d)
-		d = NewDiscard(image.Point{x + 9, 0}, FAN_NONE)
+		d = NewDiscard(self.baize, image.Point{x + 9, 0}, FAN_NONE)
 		self.discards = append(self.discards, d)
 	}
 
 	self.tableaux = []*Pile{}
 	for x := 0; x < 13; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 		self.tableaux = append(self.tableaux, t)
 	}
 
 	self.cells = []*Pile{}
 	if self.easy {
 		for x := 5; x < 8; x++ {
-			t := NewCell(image.Point{x, 0})
+			t := NewCell(self.baize, image.Point{x, 0})
 			self.cells = append(self.cells, t)
 		}
 	}
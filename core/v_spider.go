@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 I'll call the receiver anything I like, thank you
@@ -14,17 +14,17 @@ type Spider struct {
 
 func (self *Spider) BuildPiles() {
 
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, self.packs, self.suits, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, self.packs, self.suits, nil, 0)
 
 	self.discards = nil
 	for x := 2; x < 10; x++ {
-		d := NewDiscard(image.Point{x, 0}, FAN_NONE)
+		d := NewDiscard(self.baize, image.Point{x, 0}, FAN_NONE)
 		self.discards = append(self.discards, d)
 	}
 
 	self.tableaux = nil
 	for x := 0; x < 10; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 		self.tableaux = append(self.tableaux, t)
 	}
 }
@@ -50,7 +50,7 @@ func (self *Spider) StartGame() {
 			c.FlipUp()
 		}
 	}
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
 }
 
 func (*Spider) TailMoveError(tail []*Card) (bool, error) {
@@ -102,7 +102,7 @@ func (self *Spider) TailTapped(tail []*Card) {
 			}
 		}
 		if emptyTabs > 0 && tabCards >= len(self.tableaux) {
-			TheGame.UI.ToastError("All empty tableaux must be filled before dealing a new row")
+			self.baize.toastError("All empty tableaux must be filled before dealing a new row")
 		} else {
 			for _, tab := range self.tableaux {
 				MoveCard(self.stock, tab)
@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 I'll call the receiver anything I like, thank you
@@ -16,27 +16,27 @@ type Toad struct {
 
 func (self *Toad) BuildPiles() {
 
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, 2, 4, nil, 0)
-	self.waste = NewWaste(image.Point{1, 0}, FAN_RIGHT3)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, 2, 4, nil, 0)
+	self.waste = NewWaste(self.baize, image.Point{1, 0}, FAN_RIGHT3)
 
 	self.reserves = nil
-	self.reserves = append(self.reserves, NewReserve(image.Point{3, 0}, FAN_RIGHT))
+	self.reserves = append(self.reserves, NewReserve(self.baize, image.Point{3, 0}, FAN_RIGHT))
 
 	self.foundations = nil
 	for x := 0; x < 8; x++ {
-		self.foundations = append(self.foundations, NewFoundation(image.Point{x, 1}))
+		self.foundations = append(self.foundations, NewFoundation(self.baize, image.Point{x, 1}))
 	}
 
 	self.tableaux = nil
 	for x := 0; x < 8; x++ {
 		// When moving tableau piles, you must either move the whole pile or only the top card.
-		self.tableaux = append(self.tableaux, NewTableau(image.Point{x, 2}, FAN_DOWN, MOVE_ONE_OR_ALL))
+		self.tableaux = append(self.tableaux, NewTableau(self.baize, image.Point{x, 2}, FAN_DOWN, MOVE_ONE_OR_ALL))
 	}
 }
 
 func (self *Toad) StartGame() {
 
-	TheGame.Baize.SetRecycles(1)
+	self.baize.SetRecycles(1)
 
 	for n := 0; n < 20; n++ {
 		MoveCard(self.stock, self.reserves[0])
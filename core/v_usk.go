@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 I'll call the receiver anything I damn well like, thank you
@@ -21,7 +21,7 @@ type Usk struct {
 
 func (self *Usk) BuildPiles() {
 
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
 
 	self.layout = []UskPileInfo{
 		{x: 0, n: 8},
@@ -38,14 +38,14 @@ func (self *Usk) BuildPiles() {
 
 	self.foundations = nil
 	for x := 6; x < 10; x++ {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		f.SetLabel("A")
 		self.foundations = append(self.foundations, f)
 	}
 
 	self.tableaux = nil
 	for _, li := range self.layout {
-		t := NewTableau(image.Point{li.x, 1}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{li.x, 1}, FAN_DOWN, MOVE_ANY)
 		t.SetLabel(self.tableauLabel)
 		self.tableaux = append(self.tableaux, t)
 	}
@@ -62,9 +62,9 @@ func (self *Usk) dealCards() {
 
 func (self *Usk) StartGame() {
 	self.dealCards()
-	TheGame.Baize.SetRecycles(1)
+	self.baize.SetRecycles(1)
 	if self.tableauLabel == "" {
-		TheGame.UI.ToastInfo("Relaxed version - any card may be placed in an empty tableaux pile")
+		self.baize.toastInfo("Relaxed version - any card may be placed in an empty tableaux pile")
 	}
 	if DebugMode && self.stock.Len() > 0 {
 		log.Println("*** still", self.stock.Len(), "cards in Stock ***")
@@ -113,8 +113,8 @@ func (self *Usk) PileTapped(pile *Pile) {
 	if pile != self.stock {
 		return
 	}
-	if TheGame.Baize.Recycles() == 0 {
-		TheGame.UI.ToastError("No more recycles")
+	if self.baize.Recycles() == 0 {
+		self.baize.toastError("No more recycles")
 		return
 	}
 	/*
@@ -139,5 +139,5 @@ func (self *Usk) PileTapped(pile *Pile) {
 	self.stock.ReverseCards()
 	// redeal cards
 	self.dealCards()
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
 }
@@ -1,4 +1,4 @@
-package sol
+package core
 
 //lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 Receiver name will be anything I like, thank you
@@ -15,11 +15,11 @@ type Yukon struct {
 
 func (self *Yukon) BuildPiles() {
 
-	self.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
 
 	self.foundations = nil
 	for y := 0; y < 4; y++ {
-		f := NewFoundation(image.Point{8, y})
+		f := NewFoundation(self.baize, image.Point{8, y})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
@@ -27,14 +27,14 @@ func (self *Yukon) BuildPiles() {
 	self.cells = nil
 	y := 4
 	for i := 0; i < self.extraCells; i++ {
-		c := NewCell(image.Point{8, y})
+		c := NewCell(self.baize, image.Point{8, y})
 		self.cells = append(self.cells, c)
 		y += 1
 	}
 
 	self.tableaux = nil
 	for x := 0; x < 7; x++ {
-		t := NewTableau(image.Point{x, 0}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 0}, FAN_DOWN, MOVE_ANY)
 		self.tableaux = append(self.tableaux, t)
 		t.SetLabel("K")
 	}
@@ -6,6 +6,7 @@ require (
 	github.com/fogleman/gg v1.3.0
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
 	github.com/hajimehoshi/ebiten/v2 v2.6.6
+	github.com/yuin/gopher-lua v1.1.1
 	golang.org/x/image v0.12.0
 )
 
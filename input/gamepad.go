@@ -0,0 +1,39 @@
+package input
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Gamepads do not make strokes; their buttons stand in for the keys that do the same thing,
+// so a game can be played with the keyboard cursor on a handheld, or from the sofa.
+
+// GamepadKeys maps the buttons of a gamepad with the standard layout to keys
+var GamepadKeys = map[ebiten.StandardGamepadButton]ebiten.Key{
+	ebiten.StandardGamepadButtonLeftTop:       ebiten.KeyUp,     // D-pad
+	ebiten.StandardGamepadButtonLeftBottom:    ebiten.KeyDown,   // D-pad
+	ebiten.StandardGamepadButtonLeftLeft:      ebiten.KeyLeft,   // D-pad
+	ebiten.StandardGamepadButtonLeftRight:     ebiten.KeyRight,  // D-pad
+	ebiten.StandardGamepadButtonRightBottom:   ebiten.KeySpace,  // A picks up or puts down cards
+	ebiten.StandardGamepadButtonRightRight:    ebiten.KeyEscape, // B puts them back
+	ebiten.StandardGamepadButtonRightTop:      ebiten.KeyTab,    // Y goes to the next place they can go
+	ebiten.StandardGamepadButtonFrontTopLeft:  ebiten.KeyU,      // left shoulder undoes
+	ebiten.StandardGamepadButtonFrontTopRight: ebiten.KeyY,      // right shoulder redoes
+	ebiten.StandardGamepadButtonCenterRight:   ebiten.KeyMenu,   // Start
+}
+
+// AppendJustReleasedGamepadKeys appends the keys of the gamepad buttons released in the current frame;
+// gamepads without the standard layout are ignored, as there is no knowing which button is which
+func AppendJustReleasedGamepadKeys(keys []ebiten.Key) []ebiten.Key {
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for button, key := range GamepadKeys {
+			if inpututil.IsStandardGamepadButtonJustReleased(id, button) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
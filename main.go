@@ -29,9 +29,19 @@ func main() {
 	flag.BoolVar(&sol.NoGameLoad, "noload", false, "do not load saved game when starting")
 	flag.BoolVar(&sol.NoGameSave, "nosave", false, "do not save game before exit")
 	flag.BoolVar(&sol.NoScrunch, "noscrunch", false, "do not scrunch cards")
+	var exportFormat, importFile string
+	flag.StringVar(&exportFormat, "export", "", "print the position of the saved game in fc-solve or pysol format, and exit")
+	flag.StringVar(&importFile, "import", "", "start with the position in this fc-solve or pysol file")
 
 	flag.Parse()
 
+	if exportFormat != "" {
+		if err := sol.ExportSavedGame(exportFormat); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	if sol.DebugMode {
 		for i, a := range os.Args {
 			log.Println(i, a)
@@ -53,6 +63,9 @@ func main() {
 	ebiten.SetWindowTitle("Go Solitaire")
 
 	sol.NewGame() // sets sol.TheGame
+	if importFile != "" {
+		sol.TheGame.Baize.ImportPosition(importFile)
+	}
 
 	if err := ebiten.RunGame(sol.TheGame); err != nil {
 		log.Fatal(err)
@@ -45,6 +45,10 @@ var (
 	// CardOrdinalLarge is used to draw the card ordinal (J, Q, K)
 	CardOrdinalLarge font.Face
 	CardOrdinalHuge  font.Face
+	// CardIndexLarge is used to draw the card ordinal on large index faces
+	CardIndexLarge font.Face
+	// CardIndexSymbolLarge is used to draw the suit symbol beside it
+	CardIndexSymbolLarge font.Face
 )
 
 func init() {
@@ -103,6 +107,11 @@ func MakeCardFonts(cardWidth int) {
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
+	CardIndexLarge = truetype.NewFace(tt, &truetype.Options{
+		Size:    float64(cardWidth) * 0.42,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
 
 	tt, err = truetype.Parse(symbolFontBytes)
 	if err != nil {
@@ -129,6 +138,11 @@ func MakeCardFonts(cardWidth int) {
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
+	CardIndexSymbolLarge = truetype.NewFace(tt, &truetype.Options{
+		Size:    float64(cardWidth) * 0.38,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
 	CardSymbolHuge = truetype.NewFace(tt, &truetype.Options{
 		Size:    float64(cardWidth),
 		DPI:     72,
@@ -0,0 +1,122 @@
+package sol
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/cardid"
+)
+
+// All the card images, and the pile placeholders, are kept in one texture, the atlas,
+// and drawn from sub-images of it, so that Ebiten can batch the drawing of a baize into a few draw calls
+// however many cards there are. The atlas is a grid of card-sized slots, a row of 13 for each suit
+// (the first row is for cards with no suit), then a row for the backs, the shadow and the joker, then as many
+// rows as there are piles for the placeholders.
+
+const (
+	atlasColumns      = 13
+	atlasPadding      = 2 // between slots, so that filtering does not bleed one image into the next
+	atlasBack         = 13 * 5
+	atlasMovableBack  = atlasBack + 1
+	atlasShadow       = atlasBack + 2
+	atlasJoker        = atlasBack + 3
+	atlasPlaceholders = atlasColumns * 6 // the first slot of the row after the backs
+	atlasMaxSize      = 4096             // the largest texture every GPU can be relied on to have
+)
+
+// TheCardAtlas holds the card images; nil until the cards are sized
+var TheCardAtlas *ebiten.Image
+
+// atlasSlots is the number of slots TheCardAtlas has room for
+var atlasSlots int
+
+// atlasSlotRect returns where the slot is in the atlas
+func atlasSlotRect(slot int) image.Rectangle {
+	var x int = (slot % atlasColumns) * (CardWidth + atlasPadding)
+	var y int = (slot / atlasColumns) * (CardHeight + atlasPadding)
+	return image.Rect(x, y, x+CardWidth, y+CardHeight)
+}
+
+// makeCardAtlas makes a new, empty, atlas with room for the cards and this many placeholders,
+// returning false, and leaving the images to be drawn one by one, if the cards are too big for that
+func makeCardAtlas(placeholders int) bool {
+	atlasSlots = atlasPlaceholders + ((placeholders+atlasColumns-1)/atlasColumns)*atlasColumns
+	var r image.Rectangle = atlasSlotRect(atlasSlots - 1)
+	if r.Max.X > atlasMaxSize || r.Max.Y > atlasMaxSize {
+		TheCardAtlas, atlasSlots = nil, 0
+		return false
+	}
+	TheCardAtlas = ebiten.NewImage(r.Max.X, r.Max.Y)
+	return true
+}
+
+// reserveAtlasPlaceholders makes sure the atlas has room for this many placeholders,
+// copying the card images into a bigger atlas if need be; the placeholders are to be stored again
+func reserveAtlasPlaceholders(placeholders int) {
+	if TheCardAtlas == nil || atlasPlaceholders+placeholders <= atlasSlots {
+		return
+	}
+	var old *ebiten.Image = TheCardAtlas
+	if !makeCardAtlas(placeholders) {
+		return // the card images are still in the old atlas, and the placeholders will not be
+	}
+	TheCardAtlas.DrawImage(old, nil)
+	old.Dispose()
+	var sub = func(slot int) *ebiten.Image {
+		return TheCardAtlas.SubImage(atlasSlotRect(slot)).(*ebiten.Image)
+	}
+	for i := range TheCardFaceImageLibrary {
+		TheCardFaceImageLibrary[i] = sub(i)
+	}
+	CardBackImage, MovableCardBackImage, CardShadowImage = sub(atlasBack), sub(atlasMovableBack), sub(atlasShadow)
+	JokerFaceImage = sub(atlasJoker)
+}
+
+// atlasStore copies an image into a slot of the atlas, and returns the sub-image to draw it from
+func atlasStore(slot int, img *ebiten.Image) *ebiten.Image {
+	if img == nil {
+		return nil
+	}
+	var r image.Rectangle = atlasSlotRect(slot)
+	var sub *ebiten.Image = TheCardAtlas.SubImage(r).(*ebiten.Image)
+	sub.Clear()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y)) // a sub-image keeps the coordinates of the atlas
+	sub.DrawImage(img, op)
+	img.Dispose()
+	return sub
+}
+
+// packCardImages moves the card images, once made, into a new atlas;
+// the old atlas only holds the old placeholders now, which are about to be made again
+func packCardImages(placeholders int) {
+	if TheCardAtlas != nil {
+		TheCardAtlas.Dispose()
+	}
+	if !makeCardAtlas(placeholders) {
+		return
+	}
+	for i, img := range TheCardFaceImageLibrary {
+		TheCardFaceImageLibrary[i] = atlasStore(i, img)
+	}
+	CardBackImage = atlasStore(atlasBack, CardBackImage)
+	MovableCardBackImage = atlasStore(atlasMovableBack, MovableCardBackImage)
+	CardShadowImage = atlasStore(atlasShadow, CardShadowImage)
+	JokerFaceImage = atlasStore(atlasJoker, JokerFaceImage)
+}
+
+// atlasPlaceholder moves the placeholder of the i'th pile into the atlas, if there is room
+func atlasPlaceholder(i int, img *ebiten.Image) *ebiten.Image {
+	if TheCardAtlas == nil || atlasPlaceholders+i >= atlasSlots {
+		return img
+	}
+	return atlasStore(atlasPlaceholders+i, img)
+}
+
+// CardFaceImage returns the image of the face of a card
+func CardFaceImage(cid cardid.CardID) *ebiten.Image {
+	if cid.Joker() {
+		return JokerFaceImage
+	}
+	return TheCardFaceImageLibrary[(cid.Suit()*13)+(cid.Ordinal()-1)]
+}
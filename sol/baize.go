@@ -2,16 +2,19 @@ package sol
 
 import (
 	"fmt"
-	"hash/crc32"
 	"image"
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"oddstream.games/gosol/cardid"
+	"oddstream.games/gosol/core"
 	"oddstream.games/gosol/input"
+	"oddstream.games/gosol/solver"
 	"oddstream.games/gosol/sound"
 	"oddstream.games/gosol/ui"
-	"oddstream.games/gosol/util"
 )
 
 const (
@@ -23,23 +26,28 @@ const (
 	dirtyCardPositions
 )
 
-// Baize object describes the baize
+// winnableSearchTime is how long NewDeal may look for a deal that can be won
+const winnableSearchTime = 3 * time.Second
+
+// Baize object describes the baize; the game itself is played by core.Baize,
+// this object draws it and passes user input to it
 type Baize struct {
-	variant      string
-	piles        []*Pile
-	cardCount    int
-	recycles     int
-	bookmark     int
-	script       Scripter
-	undoStack    []*SavableBaize
-	dirtyFlags   uint32 // what needs doing when we Update
-	moves        int    // number of possible (not useless) moves
-	fmoves       int    // number of possible moves to a Foundation (for enabling Collect button)
+	core         *core.Baize
+	piles        []*Pile           // parallel to core.Baize.Piles()
+	cards        map[cardKey]*Card // keyed by PackSuitOrdinal, and which of the cards with it (jokers are alike)
+	recycles     int               // recycles that Stock placeholder was drawn with
+	dirtyFlags   uint32            // what needs doing when we Update
 	stroke       *input.Stroke
 	dragStart    image.Point
 	dragOffset   image.Point
-	WindowWidth  int // the most recent window width given to Layout
-	WindowHeight int // the most recent window height given to Layout
+	WindowWidth  int                // the most recent window width given to Layout
+	WindowHeight int                // the most recent window height given to Layout
+	hints        []solver.Move      // moves from the hinted position, best first
+	hintIndex    int                // the hint last shown
+	hintFrom     *core.SavableBaize // the position the hints are for
+	replay       *replaying         // not nil while a replay is being watched
+	cursor       cursor             // where the keyboard is pointing
+	search       search             // the solver, when it is running in the background
 	// hotCard      *Card
 }
 
@@ -49,13 +57,12 @@ type Baize struct {
 func NewBaize(variant string) *Baize {
 	// let WindowWidth, WindowHeight be zero, so that the first Layout will
 	// trigger card scaling and pile placement
-	var script Scripter
-	var ok bool
-	if script, ok = Variants[variant]; !ok {
-		log.Printf("do not know how to play " + variant)
+	var cb *core.Baize = core.NewBaize(variant, &TheGame.Settings.Options)
+	if cb == nil {
 		return nil
 	}
-	return &Baize{variant: variant, script: script, dirtyFlags: 0xFFFF}
+	cb.SetNotifier(TheGame.UI)
+	return &Baize{core: cb, dirtyFlags: 0xFFFF}
 }
 
 func (b *Baize) flagSet(flag uint32) bool {
@@ -74,178 +81,145 @@ func (b *Baize) setFlag(flag uint32) {
 // 	return b != nil
 // }
 
-func (b *Baize) CRC() uint32 {
-	/*
-		var crc uint = 0xFFFFFFFF
-		var mask uint
-		for _, p := range b.piles {
-			crc = crc ^ uint(p.Len())
-			for j := 7; j >= 0; j-- {
-				mask = -(crc & 1)
-				crc = (crc >> 1) ^ (0xEDB88320 & mask)
+func (b *Baize) Refan() {
+	b.setFlag(dirtyCardPositions)
+}
+
+// NewDeal restarts current variant (ie no pile building) with a new seed;
+// if only winnable deals are wanted, the deal is looked for in the background and dealt when found,
+// unless the player has moved, or another game has been dealt or loaded, by then
+func (b *Baize) NewDeal() {
+	if !TheGame.Settings.WinnableDeals[b.core.Variant()] {
+		b.newDeal(core.NewSeed(), core.SHUFFLE_GOSOL, false)
+		return
+	}
+	var game *core.Baize = b.core
+	var variant string = b.core.Variant()
+	var fromSeed uint64 = b.core.Seed()
+	var fromLen int = b.core.UndoLen()
+	var options core.Options = TheGame.Settings.Options
+	var started bool = b.startSearch(func() func() {
+		seed, winnable := solver.FindWinnableDeal(variant, &options, winnableSearchTime)
+		return func() {
+			if b.core != game || b.core.Seed() != fromSeed || b.core.UndoLen() != fromLen {
+				return
+			}
+			if !winnable {
+				TheGame.UI.ToastError("Could not find a deal that can be won, so this one may not be")
 			}
+			b.newDeal(seed, core.SHUFFLE_GOSOL, winnable)
 		}
-		return ^crc // bitwise NOT
-	*/
-	var lens []byte
-	for _, p := range b.piles {
-		lens = append(lens, byte(p.Len()))
+	})
+	if started {
+		TheGame.UI.ToastInfo("Looking for a winnable deal...")
 	}
-	return crc32.ChecksumIEEE(lens)
 }
 
-func (b *Baize) AddPile(pile *Pile) {
-	b.piles = append(b.piles, pile)
+// NewDealWithSeed restarts current variant with a specific deal number
+func (b *Baize) NewDealWithSeed(seed uint64, shuffle core.ShuffleType) {
+	b.newDeal(seed, shuffle, false)
 }
 
-func (b *Baize) Refan() {
-	b.setFlag(dirtyCardPositions)
-}
+func (b *Baize) newDeal(seed uint64, shuffle core.ShuffleType, winnable bool) {
 
-// NewDeal restarts current variant (ie no pile building) with a new seed
-func (b *Baize) NewDeal() {
+	if shuffle == core.SHUFFLE_MICROSOFT {
+		if err := b.core.MicrosoftDealError(seed); err != nil {
+			TheGame.UI.ToastError(err.Error())
+			return
+		}
+	}
 
 	// a virgin game has one state on the undo stack
-	if len(b.undoStack) > 1 && !b.Complete() {
-		percent := b.PercentComplete()
-		toastStr := TheGame.Statistics.RecordLostGame(b.variant, percent)
+	if b.core.UndoLen() > 1 && !b.core.Complete() {
+		percent := b.core.PercentComplete()
+		toastStr := TheGame.Statistics.RecordLostGame(b.core.Variant(), b.core.Seed(), percent, b.core.Winnable())
 		TheGame.UI.Toast("Fail", toastStr)
 	}
 
-	// for {
-	b.Reset()
-
-	for _, p := range b.piles {
-		p.Reset()
+	b.StopSpinning()
+	b.cards = make(map[cardKey]*Card)
+	if shuffle == core.SHUFFLE_MICROSOFT {
+		b.core.NewMicrosoftDeal(seed)
+	} else {
+		b.core.NewDeal(seed)
+	}
+	if winnable {
+		b.core.MarkWinnable()
 	}
-
-	// Stock.Fill() needs parameters
-	packs := b.script.Packs()
-	suits := b.script.Suits()
-	b.cardCount = b.script.Stock().Fill(packs, suits)
-	b.script.Stock().Shuffle()
-	b.script.StartGame()
-	b.UndoPush()
-	b.FindDestinations()
-
-	// 	if b.moves > 0 {
-	// 		break
-	// 	}
-	// 	TheGame.UI.Toast("Glass", "Found a deal with no moves")
-	// }
 
 	sound.Play("Fan")
 
-	b.setFlag(dirtyCardPositions)
+	b.refresh()
 }
 
-func (b *Baize) MirrorSlots() {
-	/*
-		0 1 2 3 4 5
-		5 4 3 2 1 0
-
-		0 1 2 3 4
-		4 3 2 1 0
-	*/
-	var minX int = 32767
-	var maxX int = 0
-	for _, p := range b.piles {
-		if p.Slot().X < 0 {
-			continue // ignore hidden pile
-		}
-		if p.Slot().X < minX {
-			minX = p.Slot().X
-		}
-		if p.Slot().X > maxX {
-			maxX = p.Slot().X
-		}
+// buildPiles makes a Pile to picture each of the core piles
+func (b *Baize) buildPiles() {
+	b.piles = nil
+	for _, cp := range b.core.Piles() {
+		b.piles = append(b.piles, NewPile(cp))
 	}
-	for _, p := range b.piles {
-		slot := p.Slot()
-		if slot.X < 0 {
-			continue // ignore hidden pile
-		}
-		p.SetSlot(image.Point{X: maxX - slot.X + minX, Y: slot.Y})
-		switch p.FanType() {
-		case FAN_RIGHT:
-			p.SetFanType(FAN_LEFT)
-		case FAN_LEFT:
-			p.SetFanType(FAN_RIGHT)
-		case FAN_RIGHT3:
-			p.SetFanType(FAN_LEFT3)
-		case FAN_LEFT3:
-			p.SetFanType(FAN_RIGHT3)
-		}
-	}
-}
-
-func (b *Baize) Reset() {
-	b.StopSpinning()
-	b.undoStack = []*SavableBaize{}
-	b.bookmark = 0
-	b.recycles = 0
-	// leave script intact
+	b.cards = make(map[cardKey]*Card)
 }
 
 // StartFreshGame resets Baize and starts a new game with a new seed
 func (b *Baize) StartFreshGame() {
-	b.Reset()
-	b.piles = []*Pile{}
-	b.script.BuildPiles()
-	if TheGame.Settings.MirrorBaize {
-		b.MirrorSlots()
-	}
-	// b.FindBuddyPiles()
+	b.StopSpinning()
+	b.core.StartFreshGame(core.NewSeed())
+	b.buildPiles()
 
-	TheGame.UI.SetTitle(b.variant)
+	TheGame.UI.SetTitle(b.core.Variant())
 	sound.Play("Fan")
 	b.dirtyFlags = 0xFFFF
 
-	b.script.StartGame()
-	b.UndoPush()
-	b.FindDestinations()
+	b.refresh()
+	if TheGame.Settings.WinnableDeals[b.core.Variant()] {
+		b.NewDeal() // a saved game loaded meanwhile is kept instead
+	}
 }
 
 func (b *Baize) ChangeVariant(newVariant string) {
 	// no longer record a lost game here because variants saved in separate .json files
-	var newScript Scripter
-	var ok bool
-	if newScript, ok = Variants[newVariant]; !ok {
+	var cb *core.Baize = core.NewBaize(newVariant, &TheGame.Settings.Options)
+	if cb == nil {
 		TheGame.UI.Toast("Error", "Do not know how to play "+newVariant)
 		return
 	}
 	b.Save()
-	b.variant = newVariant
-	TheGame.Settings.Variant = b.variant
+	b.StopSpinning()
+	cb.SetNotifier(TheGame.UI)
+	b.core = cb
+	TheGame.Settings.Variant = newVariant
 	TheGame.Settings.Save()
-	b.script = newScript
 	b.StartFreshGame()
 	if !NoGameLoad {
 		TheGame.Baize.Load()
 	}
 }
 
-func (b *Baize) SetUndoStack(undoStack []*SavableBaize) {
-	b.undoStack = undoStack
-	TheGame.UI.Toast("Glass", "Loaded a saved game of "+b.variant)
-	sav := b.UndoPeek()
-	b.updateFromSavable(sav)
-	b.FindDestinations()
+// SetGame replaces the game with one saved by core.Baize.MarshalGame
+func (b *Baize) SetGame(bytes []byte) error {
+	if err := b.core.UnmarshalGame(bytes); err != nil {
+		return err
+	}
+	TheGame.UI.Toast("Glass", "Loaded a saved game of "+b.core.Variant())
+	sound.Play("TakeOutPackage")
+	b.refresh()
 	TheGame.UI.HideFAB()
-	if b.Complete() {
+	if b.core.Complete() {
 		TheGame.UI.Toast("Complete", "Complete")
 		TheGame.UI.AddButtonToFAB("star", ebiten.KeyN)
 		b.StartSpinning()
-	} else if b.Conformant() {
+	} else if b.core.Conformant() {
 		TheGame.UI.AddButtonToFAB("done_all", ebiten.KeyC)
-	} else if b.moves == 0 {
+	} else if b.core.Moves() == 0 {
 		TheGame.UI.Toast("Error", "No movable cards")
 		TheGame.UI.AddButtonToFAB("star", ebiten.KeyN)
 		TheGame.UI.AddButtonToFAB("restore", ebiten.KeyR)
-		if b.bookmark > 0 {
+		if len(b.core.Bookmarks()) > 0 {
 			TheGame.UI.AddButtonToFAB("bookmark", ebiten.KeyL)
 		}
 	}
+	return nil
 }
 
 // findPileAt finds the Pile under the mouse position
@@ -262,7 +236,7 @@ func (b *Baize) FindPileAt(pt image.Point) *Pile {
 func (b *Baize) FindLowestCardAt(pt image.Point) *Card {
 	for _, p := range b.piles {
 		for i := p.Len() - 1; i >= 0; i-- {
-			c := p.Get(i)
+			c := p.cards[i]
 			if pt.In(c.ScreenRect()) {
 				return c
 			}
@@ -344,40 +318,40 @@ func (b *Baize) StopSpinning() {
 	b.setFlag(dirtyCardPositions)
 }
 
+// AfterUserMove tells the core that the user has moved some cards,
+// lets it do any automatic collecting, then brings the picture up to date
 func (b *Baize) AfterUserMove() {
-	b.script.AfterMove()
-	b.UndoPush()
-	b.FindDestinations()
+	b.core.AfterUserMove()
+	b.core.AfterAfterUserMove()
+	b.afterMove()
+}
+
+// afterMove brings the picture up to date and shows what the user can do next
+func (b *Baize) afterMove() {
+	b.refresh()
 	TheGame.UI.HideFAB()
-	if b.Complete() {
+	if b.core.Complete() {
 		TheGame.UI.AddButtonToFAB("star", ebiten.KeyN)
+		b.syncCards() // so the cards spinning are the cards on the baize
 		b.StartSpinning()
 		{
-			var toastStr = TheGame.Statistics.RecordWonGame(b.variant, len(b.undoStack)-1)
+			var toastStr = TheGame.Statistics.RecordWonGame(b.core.Variant(), b.core.Seed(), b.core.UndoLen()-1, b.core.Winnable())
 			TheGame.UI.Toast("Complete", toastStr)
 		}
+		b.SaveReplay()
 		ShowStatisticsDrawer()
-	} else if b.Conformant() {
+	} else if b.core.Conformant() {
 		TheGame.UI.AddButtonToFAB("done_all", ebiten.KeyC)
-	} else if b.moves == 0 {
+	} else if b.core.Moves() == 0 {
 		TheGame.UI.ToastError("No movable cards")
 		TheGame.UI.AddButtonToFAB("star", ebiten.KeyN)
 		TheGame.UI.AddButtonToFAB("restore", ebiten.KeyR)
-		if b.bookmark > 0 {
+		if len(b.core.Bookmarks()) > 0 {
 			TheGame.UI.AddButtonToFAB("bookmark", ebiten.KeyL)
 		}
 	}
 }
 
-// AfterAfterMove checks for and executes an automatic collect.
-// Kept as separated-out function at the moment, in case this
-// creates a horrible recursive loop
-func (b *Baize) AfterAfterUserMove() {
-	if b.fmoves > 0 && TheGame.Settings.AutoCollect {
-		b.Collect2()
-	}
-}
-
 /*
 InputStart finds out what object the user input is starting on
 (UI Container > Card > Pile > Baize, in that order)
@@ -387,6 +361,7 @@ If the Input starts on a Card, then a tail of cards is formed.
 */
 func (b *Baize) InputStart(v input.StrokeEvent) {
 	b.stroke = v.Stroke
+	b.hideCursor()
 
 	if con := TheGame.UI.FindContainerAt(v.X, v.Y); con != nil {
 		if w := con.FindWidgetAt(v.X, v.Y); w != nil {
@@ -397,8 +372,9 @@ func (b *Baize) InputStart(v input.StrokeEvent) {
 		}
 	} else {
 		pt := image.Pt(v.X, v.Y)
-		if card := b.FindLowestCardAt(pt); card != nil {
-			if card.Lerping() {
+		// the cards of a replay are only to be watched
+		if card := b.FindLowestCardAt(pt); card != nil && !b.Replaying() {
+			if card.Lerping() || card.core == nil {
 				TheGame.UI.Toast("Glass", "Confusing to move a moving card")
 				v.Stroke.Cancel()
 			} else {
@@ -407,7 +383,7 @@ func (b *Baize) InputStart(v input.StrokeEvent) {
 				b.stroke.SetDraggedObject(tail)
 			}
 		} else {
-			if p := b.FindPileAt(pt); p != nil {
+			if p := b.FindPileAt(pt); p != nil && !b.Replaying() {
 				b.stroke.SetDraggedObject(p)
 			} else {
 				if b.StartDrag() {
@@ -467,44 +443,25 @@ func (b *Baize) InputStop(v input.StrokeEvent) {
 		tail := obj     // alias for readability
 		card := tail[0] // for readability
 		if card.WasDragged() {
-			src := card.Owner()
 			// tap handled elsewhere
 			// tap is time-limited
 			if dst := b.LargestIntersection(card); dst == nil {
 				// println("no intersection for", c.String())
 				b.CancelTailDrag(tail)
 			} else {
-				var ok bool
-				var err error
-				// generically speaking, can this tail be moved?
-				if ok, err = src.CanMoveTail(tail); !ok {
+				crc := b.core.CRC()
+				var spoken string = announceTail(tail)
+				if err := b.core.MoveTailTo(coreTail(tail), dst.core); err != nil {
 					TheGame.UI.ToastError(err.Error())
 					b.CancelTailDrag(tail)
+				} else if crc == b.core.CRC() {
+					// dropped back onto the pile it came from
+					b.CancelTailDrag(tail)
 				} else {
-					if ok, err = dst.vtable.CanAcceptTail(tail); !ok {
-						TheGame.UI.ToastError(err.Error())
-						b.CancelTailDrag(tail)
-					} else {
-						// it's ok to move this tail
-						if src == dst {
-							b.CancelTailDrag(tail)
-						} else if ok, err = b.script.TailMoveError(tail); !ok {
-							TheGame.UI.ToastError(err.Error())
-							b.CancelTailDrag(tail)
-						} else {
-							crc := b.CRC()
-							if len(tail) == 1 {
-								MoveCard(src, dst)
-							} else {
-								MoveTail(card, dst)
-							}
-							b.StopTailDrag(tail) // do this before AfterUserMove
-							if crc != b.CRC() {
-								b.AfterUserMove()
-								b.AfterAfterUserMove()
-							}
-						}
-					}
+					b.StopTailDrag(tail) // do this before AfterUserMove
+					sound.Play("Place")
+					b.announceMove(spoken, dst)
+					b.AfterUserMove()
 				}
 			}
 		}
@@ -559,22 +516,28 @@ func (b *Baize) InputTap(v input.StrokeEvent) {
 		// if the script doesn't want to do anything, it can call pile.vtable.TailTapped
 		// which will either ignore it (eg Foundation, Discard)
 		// or use Pile.DefaultTailTapped
-		crc := b.CRC()
-		b.script.TailTapped(obj)
-		if crc != b.CRC() {
+		crc := b.core.CRC()
+		var src *Pile = obj[0].Owner()
+		var spoken string = announceTail(obj)
+		b.core.TailTapped(coreTail(obj))
+		if crc != b.core.CRC() {
 			sound.Play("Slide")
 			b.AfterUserMove()
-			b.AfterAfterUserMove()
+			if src.IsStock() {
+				b.announceDeal(src)
+			} else if dst := b.pileOf(obj[0].core.Owner()); dst != nil {
+				b.announceMove(spoken, dst) // the cards are not moved on the baize until the next Update
+			}
 		} else {
 			TheGame.UI.Toast("Error", "Attention!")
 		}
 	case *Pile:
-		crc := b.CRC()
-		b.script.PileTapped(obj)
-		if crc != b.CRC() {
+		crc := b.core.CRC()
+		b.core.PileTapped(obj.core)
+		if crc != b.core.CRC() {
 			sound.Play("Shove")
 			b.AfterUserMove()
-			b.AfterAfterUserMove()
+			b.announceDeal(obj)
 		}
 	case *Baize:
 		pt := image.Pt(v.X, v.Y)
@@ -614,6 +577,15 @@ func (b *Baize) ForeachCard(fn func(*Card)) {
 	}
 }
 
+// coreTail returns the core cards pictured by a tail of cards
+func coreTail(tail []*Card) []*core.Card {
+	var ct []*core.Card = make([]*core.Card, 0, len(tail))
+	for _, c := range tail {
+		ct = append(ct, c.core)
+	}
+	return ct
+}
+
 // ApplyToTail applies a method func to this card and all the others after it in the tail
 func (b *Baize) ApplyToTail(tail []*Card, fn func(*Card)) {
 	// https://golang.org/ref/spec#Method_expressions
@@ -648,131 +620,230 @@ func (b *Baize) CancelTailDrag(tail []*Card) {
 	b.ApplyToTail(tail, (*Card).CancelDrag)
 }
 
-func (b *Baize) powerMoves(pDraggingTo *Pile) int {
-	// (1 + number of empty freecells) * 2 ^ (number of empty columns)
-	// see http://ezinearticles.com/?Freecell-PowerMoves-Explained&id=104608
-	// and http://www.solitairecentral.com/articles/FreecellPowerMovesExplained.html
-	var emptyCells, emptyCols int
-	for _, p := range b.piles {
-		if p.Empty() {
-			switch p.vtable.(type) {
-			case *Cell:
-				emptyCells++
-			case *Tableau:
-				if p.Label() == "" && p != pDraggingTo {
-					// 'If you are moving into an empty column, then the column you are moving into does not count as empty column.'
-					emptyCols++
+// Collect2 moves as many cards as possible to the foundations,
+// as if the user had tapped them
+func (b *Baize) Collect2() {
+	crc := b.core.CRC()
+	var before int = b.foundationCards()
+	b.core.Collect2()
+	if crc != b.core.CRC() {
+		sound.Play("Place")
+		b.afterMove()
+		b.announceCollect(b.foundationCards() - before)
+	}
+}
+
+// Hint shows the best move by sliding the cards towards where they could go and back again;
+// asking again from the same position shows the next best move.
+// The hints are worked out in the background, on a copy of the game with its history.
+func (b *Baize) Hint() {
+	if b.hintFrom == b.core.UndoPeek() {
+		if len(b.hints) > 0 {
+			b.hintIndex = (b.hintIndex + 1) % len(b.hints)
+		}
+		b.showHint()
+		return
+	}
+	var cp *core.Baize = b.core.Copy()
+	cp.SetUndoStack(b.core.UndoStack())
+	var from *core.SavableBaize = b.core.UndoPeek()
+	b.startSearch(func() func() {
+		var hints []solver.Move = solver.Hints(cp)
+		return func() {
+			if b.core.UndoPeek() != from {
+				return // a move has been made since
+			}
+			// the hints are moves on the copy, so move them to the same piles of the game
+			var piles []*core.Pile = b.core.Piles()
+			for i := range hints {
+				hints[i].Src = piles[pileIndex(cp, hints[i].Src)]
+				if hints[i].Dst != nil {
+					hints[i].Dst = piles[pileIndex(cp, hints[i].Dst)]
 				}
 			}
+			b.hints, b.hintIndex, b.hintFrom = hints, 0, from
+			b.showHint()
+		}
+	})
+}
+
+// pileIndex returns where p is in the piles of cb
+func pileIndex(cb *core.Baize, p *core.Pile) int {
+	for i, cp := range cb.Piles() {
+		if cp == p {
+			return i
 		}
 	}
-	// 2^1 == 2, 2^0 == 1, 2^-1 == 0.5
-	n := (1 + emptyCells) * util.Pow(2, emptyCols)
-	// println(emptyCells, "emptyCells,", emptyCols, "emptyCols,", n, "powerMoves")
-	return n
+	return -1
 }
 
-// DoingSafeCollect return true if we are doing safe collect
-// and the safe ordinal to collect next
-func (b *Baize) DoingSafeCollect() (bool, int) {
-	if !TheGame.Settings.SafeCollect {
-		return false, 0
+// showHint shows the hint chosen by Hint
+func (b *Baize) showHint() {
+	if len(b.hints) == 0 {
+		TheGame.UI.ToastError("There are no moves")
+		return
 	}
-	if !b.script.SafeCollect() {
-		return false, 0
+	if len(b.hints) > 1 {
+		TheGame.UI.ToastInfo(fmt.Sprintf("Hint %d of %d", b.hintIndex+1, len(b.hints)))
 	}
-	var fs []*Pile = b.script.Foundations()
-	if fs == nil {
-		return false, 0
+
+	var m solver.Move = b.hints[b.hintIndex]
+	var src *Pile = b.pileOf(m.Src)
+	if m.Tap() {
+		TheGame.UI.ToastInfo("Tap the stock")
+		if waste := b.core.Script().Waste(); waste != nil && src.Len() > 0 {
+			src.Peek().LerpToAndBack(b.pileOf(waste).pos)
+		}
+		return
 	}
-	var f0 *Pile = fs[0]
-	if f0 == nil {
-		return false, 0
+	var dst *Pile = b.pileOf(m.Dst)
+	var pos image.Point = dst.pos
+	if dst.Len() > 0 {
+		pos = dst.PosAfter(dst.Peek())
 	}
-	if f0.Label() != "A" {
-		return false, 0 // eg Duchess
+	var tail []*Card = src.cards[src.Len()-m.Count:]
+	for _, c := range tail {
+		// keep the tail fanned the way it is
+		c.LerpToAndBack(pos.Add(c.pos.Sub(tail[0].pos)))
 	}
-	var lowest int = 99
-	for _, f := range fs {
-		if f.Empty() {
-			// it's okay to collect aces and twos to start with
-			return true, 2
-		}
-		var card *Card = f.Peek()
-		if card.Ordinal() < lowest {
-			lowest = card.Ordinal()
+}
+
+// pileOf returns the Pile that pictures a core pile
+func (b *Baize) pileOf(cp *core.Pile) *Pile {
+	for i, p := range b.core.Piles() {
+		if p == cp {
+			return b.piles[i]
 		}
 	}
-	return true, lowest + 1
+	return nil
 }
 
-// collectFromPile is a helper function for Collect2()
-func (b *Baize) collectFromPile(pile *Pile) int {
-	if pile == nil {
-		return 0
-	}
-	var cardsMoved int = 0
-	for _, fp := range b.script.Foundations() {
-		for {
-			var card *Card = pile.Peek()
-			if card == nil {
-				return cardsMoved
+// Winnable asks the solver if the game can still be won from the current position;
+// the answer comes later, and is forgotten if a move has been made by then
+func (b *Baize) Winnable() {
+	var cp *core.Baize = b.core.Copy()
+	var from *core.SavableBaize = b.core.UndoPeek()
+	var started bool = b.startSearch(func() func() {
+		result, _, err := solver.Solve(cp, solver.DefaultMaxNodes(cp))
+		return func() {
+			if b.core.UndoPeek() != from {
+				return
 			}
-			ok, _ := fp.vtable.CanAcceptTail([]*Card{card})
-			if !ok {
-				break // done with this foundation, try another
+			if err != nil {
+				TheGame.UI.ToastError(err.Error())
+				return
 			}
-			if ok, safeOrd := b.DoingSafeCollect(); ok {
-				if card.Ordinal() > safeOrd {
-					// can't toast here, collect all will create a lot of toasts
-					// TheGame.UI.Toast("Glass", fmt.Sprintf("Unsafe to collect %s", card.String()))
-					break // done with this foundation, try another
-				}
+			switch result {
+			case solver.SOLVABLE:
+				TheGame.UI.ToastInfo("This game can still be won")
+			case solver.UNSOLVABLE:
+				TheGame.UI.ToastError("This game cannot be won from here")
+			default:
+				TheGame.UI.ToastInfo("Gave up trying to tell if this game can still be won")
 			}
-			MoveCard(pile, fp)
-			b.AfterUserMove() // does an undoPush()
-			b.AfterAfterUserMove()
-			cardsMoved += 1
 		}
+	})
+	if started {
+		TheGame.UI.ToastInfo("Looking for a way to win...")
 	}
-	return cardsMoved
 }
 
-// Collect2 should be exactly the same as the user tapping repeatedly on the
-// waste, cell, reserve and tableau piles.
-// nb there is no collecting to discard piles, they are optional and presence of
-// cards in them does not signify a complete game.
-// It's called Collect2 because it's the third or fourth rewrite of a
-// basic and seemingly simple function.
-func (b *Baize) Collect2() {
-	for {
-		var cardsMoved int = b.collectFromPile(b.script.Waste())
-		for _, pile := range b.script.Cells() {
-			cardsMoved += b.collectFromPile(pile)
-		}
-		for _, pile := range b.script.Reserves() {
-			cardsMoved += b.collectFromPile(pile)
-		}
-		for _, pile := range b.script.Tableaux() {
-			cardsMoved += b.collectFromPile(pile)
-		}
-		if cardsMoved == 0 {
-			break
+// Undo reverts the Baize state to it's previous state
+func (b *Baize) Undo() {
+	if err := b.core.Undo(); err != nil {
+		TheGame.UI.ToastError(err.Error())
+		return
+	}
+	sound.Play("TakeOutPackage")
+	b.refresh()
+}
+
+// Redo makes the move that was last undone again
+func (b *Baize) Redo() {
+	if err := b.core.Redo(); err != nil {
+		TheGame.UI.ToastError(err.Error())
+		return
+	}
+	sound.Play("TakeOutPackage")
+	b.refresh()
+}
+
+// ShowBranchDrawer lists the lines of play in this game, so the player can go to the end of one
+func (b *Baize) ShowBranchDrawer() {
+	var content []string
+	for i, bi := range b.core.Branches() {
+		var str string = fmt.Sprintf("%d. %d moves, %d%%", i+1, bi.Moves, bi.Percent)
+		if bi.Current {
+			str += " (playing)"
 		}
+		content = append(content, str)
 	}
-	// if ThePreferences.SafeCollect && b.script.SafeCollect() && b.fmoves > 0 {
-	// 	TheGame.UI.Toast("Glass", "Not safe to collect card(s)")
-	// }
+	TheGame.UI.ShowVariantPickerEx(content, "GotoBranch")
 }
 
-func (b *Baize) MaxSlotX() int {
-	var maxX int
-	for _, p := range b.piles {
-		if p.Slot().X > maxX {
-			maxX = p.Slot().X
+// GotoBranch goes to the end of the i'th line of play
+func (b *Baize) GotoBranch(i int) {
+	if err := b.core.GotoBranch(i); err != nil {
+		TheGame.UI.ToastError(err.Error())
+		return
+	}
+	sound.Play("TakeOutPackage")
+	b.refresh()
+}
+
+func (b *Baize) RestartDeal() {
+	if err := b.core.RestartDeal(); err != nil {
+		TheGame.UI.ToastError(err.Error())
+		return
+	}
+	sound.Play("TakeOutPackage")
+	b.refresh()
+}
+
+// AddBookmark bookmarks the current position
+func (b *Baize) AddBookmark() {
+	name, err := b.core.AddBookmark()
+	if err != nil {
+		TheGame.UI.ToastError(err.Error())
+		return
+	}
+	b.UpdateDrawers()
+	TheGame.UI.ToastInfo(fmt.Sprintf("Position bookmarked as %s", name))
+}
+
+// ShowBookmarkDrawer lists the bookmarks of this game, so the player can go to one or delete it
+func (b *Baize) ShowBookmarkDrawer() {
+	var names, summaries []string
+	var branches int = len(b.core.Branches())
+	for _, bm := range b.core.Bookmarks() {
+		var str string = fmt.Sprintf("%d moves, %d%% complete", bm.Moves(), bm.Percent)
+		if branches > 1 {
+			str = fmt.Sprintf("%s, branch %d", str, bm.Branch+1)
 		}
+		names = append(names, bm.Name)
+		summaries = append(summaries, str)
 	}
-	return maxX
+	TheGame.UI.ShowBookmarkDrawer(names, summaries)
+}
+
+// GotoBookmark goes to a bookmarked position
+func (b *Baize) GotoBookmark(name string) {
+	if err := b.core.GotoBookmark(name); err != nil {
+		TheGame.UI.ToastError(err.Error())
+		return
+	}
+	sound.Play("TakeOutPackage")
+	b.refresh()
+}
+
+// DeleteBookmark forgets a bookmark, and shows the ones that are left
+func (b *Baize) DeleteBookmark(name string) {
+	if err := b.core.DeleteBookmark(name); err != nil {
+		TheGame.UI.ToastError(err.Error())
+		return
+	}
+	b.UpdateDrawers()
+	b.ShowBookmarkDrawer()
 }
 
 // ScaleCards calculates new width/height of cards and margins
@@ -789,7 +860,7 @@ func (b *Baize) ScaleCards() bool {
 	var OldWidth = CardWidth
 	var OldHeight = CardHeight
 
-	var maxX int = b.MaxSlotX()
+	var maxX int = b.core.MaxSlotX()
 
 	/*
 		71 x 96 = 1:1.352 (Microsoft retro)
@@ -839,67 +910,123 @@ func (b *Baize) ScaleCards() bool {
 	return CardWidth != OldWidth || CardHeight != OldHeight
 }
 
-func (b *Baize) PercentComplete() int {
-	var pairs, unsorted, percent int
+// refresh is called after the core has changed, to bring the picture up to date
+func (b *Baize) refresh() {
 	for _, p := range b.piles {
-		if p.Len() > 1 {
-			pairs += p.Len() - 1
+		if p.label != p.core.Label() {
+			p.label = p.core.Label()
+			b.setFlag(dirtyPileBackgrounds) // recreate placeholder
 		}
-		unsorted += p.vtable.UnsortedPairs()
 	}
-	// TheGame.UI.SetMiddle(fmt.Sprintf("%d/%d", pairs-unsorted, pairs))
-	percent = (int)(100.0 - util.MapValue(float64(unsorted), 0, float64(pairs), 0.0, 100.0))
-	return percent
+	if b.recycles != b.core.Recycles() {
+		b.recycles = b.core.Recycles()
+		b.setFlag(dirtyPileBackgrounds) // recreate Stock placeholder
+	}
+	b.setFlag(dirtyCardPositions)
+	b.CursorCancel() // the cards picked up may have moved
+
+	b.UpdateToolbar()
+	b.UpdateDrawers()
+	b.UpdateStatusbar()
+
+	if !TheGame.Settings.AlwaysShowMovableCards {
+		TheGame.Settings.ShowMovableCards = false
+	}
 }
 
-func (b *Baize) Recycles() int {
-	return b.recycles
+// cardKey finds the Card that pictures a core card; the jokers of a pack are alike,
+// so they are told apart by the order they are found in
+type cardKey struct {
+	id cardid.CardID
+	n  int
 }
 
-func (b *Baize) SetRecycles(recycles int) {
-	b.recycles = recycles
-	b.setFlag(dirtyPileBackgrounds) // recreate Stock placeholder
+// syncCards makes each Pile hold the Cards that picture the cards in its core pile.
+// A Card is created the first time its core card is seen, face down where the
+// stock is, so it can be seen to be dealt
+func (b *Baize) syncCards() {
+	var stockPos image.Point
+	for _, p := range b.piles {
+		if p.IsStock() {
+			stockPos = p.pos
+		}
+	}
+	var seen map[cardid.CardID]int = make(map[cardid.CardID]int)
+	for i, cp := range b.core.Piles() {
+		var p *Pile = b.piles[i]
+		p.cards = p.cards[:0]
+		for _, cc := range cp.Cards() {
+			var id cardid.CardID = cc.ID().PackSuitOrdinal()
+			var key cardKey = cardKey{id: id, n: seen[id]}
+			seen[id]++
+			c, ok := b.cards[key]
+			if !ok {
+				c = NewCard(cc.ID().SetProne(true), stockPos)
+				b.cards[key] = c
+			}
+			c.core = cc
+			c.SetOwner(p)
+			c.SetFlip(cc.Prone())
+			p.cards = append(p.cards, c)
+		}
+	}
 }
 
 func (b *Baize) UpdateToolbar() {
-	TheGame.UI.EnableWidget("toolbarUndo", len(b.undoStack) > 1)
-	TheGame.UI.EnableWidget("toolbarCollect", b.fmoves > 0)
+	if b.replay != nil {
+		// undo and redo step through the replay
+		TheGame.UI.EnableWidget("toolbarUndo", b.replay.replayer.Index() > 0)
+		TheGame.UI.EnableWidget("toolbarRedo", b.replay.replayer.Index() < b.replay.replayer.Len())
+		TheGame.UI.EnableWidget("toolbarCollect", false)
+		return
+	}
+	TheGame.UI.EnableWidget("toolbarUndo", b.core.UndoLen() > 1)
+	TheGame.UI.EnableWidget("toolbarRedo", b.core.RedoLen() > 0)
+	TheGame.UI.EnableWidget("toolbarCollect", b.core.FMoves() > 0)
 }
 
 func (b *Baize) UpdateStatusbar() {
-	if b.script.Stock().Hidden() {
+	var script core.Scripter = b.core.Script()
+	if script.Stock().Hidden() {
 		TheGame.UI.SetStock(-1)
 	} else {
-		TheGame.UI.SetStock(b.script.Stock().Len())
+		TheGame.UI.SetStock(script.Stock().Len())
 	}
-	if b.script.Waste() == nil {
+	if script.Waste() == nil {
 		TheGame.UI.SetWaste(-1) // previous variant may have had a waste, and this one does not
 	} else {
-		TheGame.UI.SetWaste(b.script.Waste().Len())
+		TheGame.UI.SetWaste(script.Waste().Len())
 	}
 	// if DebugMode {
 	// 	TheGame.UI.SetMiddle(fmt.Sprintf("MOVES: %d,%d", b.moves, b.fmoves))
 	// }
-	TheGame.UI.SetMiddle(fmt.Sprintf("MOVES: %d", len(b.undoStack)-1))
-	TheGame.UI.SetPercent(b.PercentComplete())
-}
-
-func (b *Baize) UpdateDrawers() {
-	TheGame.UI.EnableWidget("restartDeal", len(b.undoStack) > 1)
-	TheGame.UI.EnableWidget("gotoBookmark", b.bookmark > 0)
+	if b.replay != nil {
+		TheGame.UI.SetMiddle(fmt.Sprintf("REPLAY: %d/%d", b.replay.replayer.Index(), b.replay.replayer.Len()))
+	} else {
+		TheGame.UI.SetMiddle(fmt.Sprintf("MOVES: %d", b.core.UndoLen()-1))
+	}
+	TheGame.UI.SetPercent(b.core.PercentComplete())
+	if b.core.Shuffle() == core.SHUFFLE_MICROSOFT {
+		TheGame.UI.SetDeal("MS " + b.DealNumber())
+	} else {
+		TheGame.UI.SetDeal(b.DealNumber())
+	}
 }
 
-func (b *Baize) Conformant() bool {
-	for _, p := range b.piles {
-		if !p.vtable.Conformant() {
-			return false
-		}
+// DealNumber returns the number of the current deal as a string,
+// or an empty string if it is not known
+func (b *Baize) DealNumber() string {
+	if b.core.Seed() == 0 {
+		return ""
 	}
-	return true
+	return strconv.FormatUint(b.core.Seed(), 10)
 }
 
-func (b *Baize) Complete() bool {
-	return b.script.Complete()
+func (b *Baize) UpdateDrawers() {
+	// a replay is stopped by anything in the drawers, so they are about the game
+	TheGame.UI.EnableWidget("restartDeal", b.game().UndoLen() > 1)
+	TheGame.UI.EnableWidget("branches", len(b.game().Branches()) > 1)
+	TheGame.UI.EnableWidget("bookmarks", len(b.game().Bookmarks()) > 0)
 }
 
 // Layout implements ebiten.Game's Layout.
@@ -935,6 +1062,7 @@ func (b *Baize) Layout(outsideWidth, outsideHeight int) (int, int) {
 		}
 		if b.flagSet(dirtyCardImages) {
 			CreateCardImages()
+			b.setFlag(dirtyPileBackgrounds) // the placeholders go into the new atlas too
 			// b.clearFlag(dirtyCardImages)
 		}
 		if b.flagSet(dirtyPilePositions) {
@@ -948,9 +1076,10 @@ func (b *Baize) Layout(outsideWidth, outsideHeight int) (int, int) {
 		}
 		if b.flagSet(dirtyPileBackgrounds) {
 			if !(CardWidth == 0 || CardHeight == 0) {
-				for _, p := range b.piles {
+				reserveAtlasPlaceholders(len(b.piles))
+				for i, p := range b.piles {
 					if !p.Hidden() {
-						p.img = p.vtable.Placeholder()
+						p.img = atlasPlaceholder(i, p.Placeholder())
 					}
 				}
 			}
@@ -961,6 +1090,7 @@ func (b *Baize) Layout(outsideWidth, outsideHeight int) (int, int) {
 			// b.clearFlag(dirtyWindowSize)
 		}
 		if b.flagSet(dirtyCardPositions) {
+			b.syncCards()
 			for _, p := range b.piles {
 				p.Scrunch()
 			}
@@ -996,8 +1126,16 @@ func (b *Baize) Update() error {
 		p.Update()
 	}
 
-	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
-		if inpututil.IsKeyJustReleased(k) {
+	b.updateReplay()
+	b.finishSearch()
+
+	if !TheGame.UI.CapturingKeys() {
+		for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+			if inpututil.IsKeyJustReleased(k) {
+				Execute(k)
+			}
+		}
+		for _, k := range input.AppendJustReleasedGamepadKeys(nil) {
 			Execute(k)
 		}
 	}
@@ -1022,6 +1160,7 @@ func (b *Baize) Draw(screen *ebiten.Image) {
 	for _, p := range b.piles {
 		p.DrawDraggingCards(screen)
 	}
+	b.drawCursor(screen)
 	// if b.hotCard != nil {
 	// 	b.hotCard.Draw(screen)
 	// }
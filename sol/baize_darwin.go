@@ -6,7 +6,7 @@ import (
 )
 
 func (b *Baize) Wikipedia() {
-	cmd := exec.Command("open", b.script.Wikipedia())
+	cmd := exec.Command("open", b.core.Script().Wikipedia())
 	if cmd == nil {
 		return
 	}
@@ -15,3 +15,8 @@ func (b *Baize) Wikipedia() {
 		log.Println(err)
 	}
 }
+
+// speechCommand speaks msg with the system voice
+func speechCommand(msg string) *exec.Cmd {
+	return exec.Command("say", msg)
+}
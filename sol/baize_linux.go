@@ -6,7 +6,7 @@ import (
 )
 
 func (b *Baize) Wikipedia() {
-	var cmd *exec.Cmd = exec.Command("xdg-open", b.script.Wikipedia())
+	var cmd *exec.Cmd = exec.Command("xdg-open", b.core.Script().Wikipedia())
 	if cmd != nil {
 		err := cmd.Start()
 		if err != nil {
@@ -14,3 +14,8 @@ func (b *Baize) Wikipedia() {
 		}
 	}
 }
+
+// speechCommand speaks msg with speech-dispatcher, if it is installed
+func speechCommand(msg string) *exec.Cmd {
+	return exec.Command("spd-say", "--wait", msg)
+}
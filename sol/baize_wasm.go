@@ -5,5 +5,5 @@ import (
 )
 
 func (b *Baize) Wikipedia() {
-	js.Global().Get("window").Call("open", b.script.Wikipedia())
+	js.Global().Get("window").Call("open", b.core.Script().Wikipedia())
 }
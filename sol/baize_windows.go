@@ -3,11 +3,23 @@ package sol
 import (
 	"log"
 	"os/exec"
+	"strings"
+	"syscall"
 )
 
 func (b *Baize) Wikipedia() {
-	err := exec.Command("rundll32", "url.dll,FileProtocolHandler", b.script.Wikipedia()).Start()
+	err := exec.Command("rundll32", "url.dll,FileProtocolHandler", b.core.Script().Wikipedia()).Start()
 	if err != nil {
 		log.Println(err)
 	}
 }
+
+// speechCommand speaks msg with the system voice, through PowerShell
+func speechCommand(msg string) *exec.Cmd {
+	var script string = "Add-Type -AssemblyName System.Speech; (New-Object System.Speech.Synthesis.SpeechSynthesizer).Speak('" +
+		strings.ReplaceAll(msg, "'", "''") + "')"
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	// no console window flashing up for each announcement; 0x08000000 is CREATE_NO_WINDOW
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: 0x08000000}
+	return cmd
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"oddstream.games/gosol/cardid"
+	"oddstream.games/gosol/core"
 	"oddstream.games/gosol/util"
 )
 
@@ -15,24 +16,25 @@ import (
 	Cards have several states: idle, being dragged, transitioning, shaking, spinning, flipping.
 	You'd think that cards should have a 'state' enum, but the states can overlap (eg a card
 	can transition and flip at the same time).
+
+	A Card is the picture of a core.Card; the rules live in the core.Card,
+	this just remembers where it's drawn and how it's moving.
 */
 
 // Card object
 type Card struct {
-	id         cardid.CardID
+	id         cardid.CardID // what is being shown, including prone flag
+	core       *core.Card    // set by Baize.syncCards
 	owningPile *Pile
 	pos        image.Point
 
-	// tap things
-	tapDestination *Pile
-	tapWeight      int
-
 	// lerping things
 	src           image.Point // lerp origin
 	dst           image.Point // lerp destination
 	aniSpeed      float64
 	lerpStartTime time.Time
 	lerping       bool
+	lerpBack      bool // when the lerp is done, lerp back to where it started (showing a hint)
 
 	// dragging things
 	dragStart    image.Point // starting point for dragging
@@ -50,36 +52,21 @@ type Card struct {
 }
 
 // NewCard is a factory for Card objects
-func NewCard(pack, suit, ordinal int, pos image.Point) Card {
+func NewCard(id cardid.CardID, pos image.Point) *Card {
 	// be nice to start the cards in the middle of the screen,
 	// but the screen will be 0,0 when app starts
 	// and ebiten.WindowSize() only works on desktops
 	// Stock is usually at (slot) 0,0 which is half a card width/height into the baize, so...
-	c := Card{id: cardid.NewCardID(pack, suit, ordinal), pos: pos}
-	// a joker ID will be created by having NOSUIT (0) and ordinal == 0
-	// could do c.lerpStep = 1.0 here, but a freshly created card is soon SetPosition()'ed
-	return c
+	// a freshly created card is soon LerpTo()'ed
+	return &Card{id: id, pos: pos}
 }
 
-// func (c *Card) Valid() bool {
-// 	return c != nil
-// }
-
 // String satisfies the Stringer interface (defined by fmt package)
 func (c *Card) String() string {
 	return c.id.String()
 }
 
 func (c *Card) Owner() *Pile {
-	// for _, p := range TheGame.Baize.piles {
-	// 	for _, card := range p.cards {
-	// 		if cardid.SameCardAndPack(c.id, card.id) {
-	// 			return p
-	// 		}
-	// 	}
-	// }
-	// log.Panicf("%s has no owner", c.id)
-	// return nil
 	return c.owningPile
 }
 
@@ -151,6 +138,7 @@ func (c *Card) LerpTo(dst image.Point) {
 	}
 
 	c.lerping = true
+	c.lerpBack = false
 	c.src = c.pos
 	c.dst = dst
 	// refanning waste cards can flutter with slow AniSpeed, so go faster if not far to go
@@ -163,6 +151,15 @@ func (c *Card) LerpTo(dst image.Point) {
 	c.lerpStartTime = time.Now()
 }
 
+// LerpToAndBack moves this Card to pos and back again, to show where it could go
+func (c *Card) LerpToAndBack(dst image.Point) {
+	if c.Lerping() || c.Dragging() || c.Spinning() {
+		return
+	}
+	c.LerpTo(dst)
+	c.lerpBack = c.lerping
+}
+
 // StartDrag informs card that it is being dragged
 func (c *Card) StartDrag() {
 	if c.Lerping() {
@@ -253,8 +250,6 @@ func (c *Card) StartSpinning() {
 	c.directionX = rand.Intn(9) - 4
 	c.directionY = rand.Intn(9) - 3 // favor falling downwards
 	c.spin = rand.Float64() - 0.5
-	c.tapDestination = nil
-	c.tapWeight = 0
 	// delay start of spinning to allow cards to be seen to go/finish their trip to foundations
 	// https://stackoverflow.com/questions/67726230/creating-a-time-duration-from-float64-seconds
 	d := time.Duration(TheGame.Settings.AniSpeed * float64(time.Second))
@@ -338,6 +333,9 @@ func (c *Card) Update() error {
 			c.pos.Y = int(util.Smoothstep(float64(c.src.Y), float64(c.dst.Y), t))
 		} else {
 			c.lerping = false
+			if c.lerpBack {
+				c.LerpTo(c.src)
+			}
 		}
 	}
 
@@ -377,7 +375,7 @@ func (c *Card) Draw(screen *ebiten.Image) {
 	if c.flipDirection < 0 {
 		if c.Prone() {
 			// card is getting narrower, and it's going to show face down, but show face up
-			img = TheCardFaceImageLibrary[(c.Suit()*13)+(c.Ordinal()-1)]
+			img = CardFaceImage(c.id)
 		} else {
 			// card is getting narrower, and it's going to show face up, but show face down
 			img = CardBackImage
@@ -386,7 +384,7 @@ func (c *Card) Draw(screen *ebiten.Image) {
 		if c.Prone() {
 			img = CardBackImage
 		} else {
-			img = TheCardFaceImageLibrary[(c.Suit()*13)+(c.Ordinal()-1)]
+			img = CardFaceImage(c.id)
 		}
 	}
 
@@ -455,9 +453,9 @@ func (c *Card) Draw(screen *ebiten.Image) {
 			// nb this will color all the stock cards, not just the top card
 			img = MovableCardBackImage
 		} else {
-			if !c.Flipping() && c.tapWeight != 0 {
+			if !c.Flipping() && !c.Spinning() && c.core != nil && c.core.TapWeight() != 0 {
 				// c.destinations has been sorted so weightiest is first
-				switch c.tapWeight {
+				switch c.core.TapWeight() {
 				case 1: // Cell
 					op.ColorM.Scale(1.0, 1.0, 0.9, 1)
 				case 2: // Normal
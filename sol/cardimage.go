@@ -3,6 +3,7 @@ package sol
 import (
 	"image/color"
 	"log"
+	"math"
 
 	"github.com/fogleman/gg"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -103,7 +104,7 @@ var Pips [13][]PipInfo = [13][]PipInfo{
 func cardColor(cid cardid.CardID) color.RGBA {
 	suit := cid.Suit()
 	if TheGame.Settings.ColorfulCards {
-		switch TheGame.Baize.script.CardColors() {
+		switch TheGame.Baize.core.Script().CardColors() {
 		case 4:
 			switch suit {
 			case cardid.NOSUIT:
@@ -170,6 +171,11 @@ func createFaceImage(ID cardid.CardID) *ebiten.Image {
 	// 	cardColor.A = 64
 	// }
 
+	if TheGame.Settings.LargeIndices {
+		drawLargeIndices(dc, cardOrdinal, suitRune, cardColor)
+		return ebiten.NewImageFromImage(dc.Image())
+	}
+
 	// draw the card ordinals in top left and bottom right corners
 	dc.SetColor(cardColor)
 	if cardOrdinal == 10 {
@@ -239,6 +245,29 @@ func createFaceImage(ID cardid.CardID) *ebiten.Image {
 	return ebiten.NewImageFromImage(dc.Image())
 }
 
+// drawLargeIndices draws the ordinal and suit across the top of the face, large enough to be read
+// when the cards are fanned, and again upside down at the bottom, with one big suit symbol between
+func drawLargeIndices(dc *gg.Context, cardOrdinal int, suitRune rune, cardColor color.RGBA) {
+	w := float64(dc.Width())
+	h := float64(dc.Height())
+
+	dc.SetColor(cardColor)
+	for i := 0; i < 2; i++ {
+		dc.SetFontFace(schriftbank.CardIndexLarge)
+		dc.DrawStringAnchored(util.OrdinalToShortString(cardOrdinal), w*0.3, h*0.16, 0.5, 0.4)
+		if suitRune != 0 {
+			dc.SetFontFace(schriftbank.CardIndexSymbolLarge)
+			dc.DrawStringAnchored(string(suitRune), w*0.72, h*0.16, 0.5, 0.4)
+		}
+		dc.RotateAbout(gg.Radians(180), w*0.5, h*0.5)
+	}
+	if suitRune != 0 {
+		dc.SetFontFace(schriftbank.CardSymbolLarge)
+		dc.DrawStringAnchored(string(suitRune), w*0.5, h*0.5, 0.5, 0.5)
+	}
+	dc.Stroke()
+}
+
 /***
 func createSimpleFaceImage(ID CardID) *ebiten.Image {
 	w := float64(CardWidth)
@@ -281,6 +310,51 @@ func createSimpleFaceImage(ID CardID) *ebiten.Image {
 }
 ***/
 
+// createJokerFaceImage draws the face of a joker, which has no ordinal or suit,
+// so J over K is written in the corners, and a star drawn in the middle
+func createJokerFaceImage() *ebiten.Image {
+	w := float64(CardWidth)
+	h := float64(CardHeight)
+
+	dc := gg.NewContext(CardWidth, CardHeight)
+
+	dc.SetColor(ExtendedColors[TheGame.Settings.CardFaceColor])
+	dc.DrawRoundedRectangle(0, 0, w, h, CardCornerRadius)
+	dc.Fill()
+
+	dc.SetLineWidth(1)
+	dc.SetRGBA(0, 0, 0, 0.1)
+	dc.DrawRoundedRectangle(1, 1, w-2, h-2, CardCornerRadius)
+	dc.Stroke()
+
+	dc.SetColor(BasicColors["Purple"])
+	dc.SetFontFace(schriftbank.CardOrdinalSmall)
+	for i := 0; i < 2; i++ {
+		dc.DrawStringAnchored("J", w*COTLX, h*COTLY, 0.5, 0.4)
+		dc.DrawStringAnchored("K", w*COTLX, h*(COTLY+0.14), 0.5, 0.4)
+		dc.RotateAbout(gg.Radians(180), w*0.5, h*0.5)
+	}
+
+	dc.SetRGBA(0, 0, 0, 0.05)
+	dc.DrawRectangle(w*0.25, h*0.25, w*0.5, h*0.5)
+	dc.Fill()
+
+	// a five pointed star, its points every 72 degrees, with the inner corners between them
+	dc.SetColor(BasicColors["Purple"])
+	for i := 0; i < 10; i++ {
+		var r float64 = w * 0.2
+		if i%2 == 1 {
+			r *= 0.4
+		}
+		var a float64 = gg.Radians(float64(i)*36 - 90)
+		dc.LineTo(w*0.5+r*math.Cos(a), h*0.5+r*math.Sin(a))
+	}
+	dc.ClosePath()
+	dc.Fill()
+
+	return ebiten.NewImageFromImage(dc.Image())
+}
+
 func CreateCardBackImage(color string) *ebiten.Image {
 	w := float64(CardWidth)
 	h := float64(CardHeight)
@@ -338,4 +412,7 @@ func CreateCardImages() {
 	CardBackImage = CreateCardBackImage(TheGame.Settings.CardBackColor)
 	MovableCardBackImage = CreateCardBackImage(TheGame.Settings.MovableCardBackColor)
 	CardShadowImage = CreateCardShadowImage()
+	JokerFaceImage = createJokerFaceImage()
+	applyCardPack()
+	packCardImages(len(TheGame.Baize.piles))
 }
@@ -0,0 +1,101 @@
+package sol
+
+import (
+	"image"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/draw"
+	"oddstream.games/gosol/cardid"
+)
+
+// A card pack is a directory of images in cards/ in the config directory, drawn instead of the
+// card faces and backs gosol makes itself. Images are named after the card in text notation,
+// eg AS.png, TH.png, JK.png for jokers, back.png and movable.png for the back of a card that can be moved,
+// or come from one image, a sprite sheet, with a manifest.json saying where each card is:
+//
+//	{
+//		"image": "sheet.png",
+//		"width": 140, "height": 190,
+//		"cards": {"AS": [0, 0], "2S": [140, 0], "back": [0, 760], "JK": [140, 760, 140, 190]}
+//	}
+//
+// where each card is the x, y of its top left corner, and optionally its width and height.
+// Images are scaled to the size of the cards; any card the pack does not have is made as usual.
+
+// cardPackManifest describes a sprite sheet
+type cardPackManifest struct {
+	Image         string
+	Width, Height int
+	Cards         map[string][]int
+}
+
+// cardPackImages holds the images of the card pack in use, as loaded, so they need
+// only be scaled again when the cards change size
+var cardPackImages map[string]image.Image
+
+// cardPackImagesName is the name of the card pack in cardPackImages
+var cardPackImagesName string
+
+// cardPackName is the name of a card face in a card pack, eg "7H"
+func cardPackName(suit, ord int) string {
+	return cardid.NewCardID(0, suit, ord).Notation()
+}
+
+// applyCardPack replaces the card images already made with those in the card pack chosen in the settings
+func applyCardPack() {
+	if TheGame.Settings.CardPack == "" {
+		return
+	}
+	if cardPackImagesName != TheGame.Settings.CardPack {
+		images, err := loadCardPack(TheGame.Settings.CardPack)
+		if err != nil {
+			log.Println(err)
+			TheGame.UI.ToastError(err.Error())
+			TheGame.Settings.CardPack = ""
+			return
+		}
+		cardPackImages, cardPackImagesName = images, TheGame.Settings.CardPack
+	}
+	for _, suit := range []int{cardid.CLUB, cardid.DIAMOND, cardid.HEART, cardid.SPADE} {
+		for ord := 1; ord < 14; ord++ {
+			if img, ok := cardPackImages[cardPackName(suit, ord)]; ok {
+				TheCardFaceImageLibrary[(suit*13)+(ord-1)] = scaleCardImage(img)
+			}
+		}
+	}
+	if img, ok := cardPackImages["JK"]; ok {
+		JokerFaceImage = scaleCardImage(img)
+	}
+	if img, ok := cardPackImages["back"]; ok {
+		CardBackImage = scaleCardImage(img)
+	}
+	if img, ok := cardPackImages["movable"]; ok {
+		MovableCardBackImage = scaleCardImage(img)
+	}
+}
+
+// scaleCardImage makes an image the size of the cards
+func scaleCardImage(img image.Image) *ebiten.Image {
+	var dst *image.RGBA = image.NewRGBA(image.Rect(0, 0, CardWidth, CardHeight))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+	return ebiten.NewImageFromImage(dst)
+}
+
+// ShowCardPackPicker lets the player choose a card pack, or the cards gosol makes itself
+func ShowCardPackPicker() {
+	TheGame.UI.ShowVariantPickerEx(append([]string{noCardPack}, CardPackNames()...), "ChangeCardPack")
+}
+
+// noCardPack is how the cards gosol makes itself are listed in the card pack picker
+const noCardPack = "Drawn by gosol"
+
+// ChangeCardPack switches to the named card pack, redrawing the cards
+func (b *Baize) ChangeCardPack(name string) {
+	if name == noCardPack {
+		name = ""
+	}
+	TheGame.Settings.CardPack = name
+	b.setFlag(dirtyCardImages)
+	TheGame.Settings.Save()
+}
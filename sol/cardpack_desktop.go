@@ -0,0 +1,105 @@
+//go:build linux || windows || android || darwin
+
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"path"
+	"strings"
+
+	"oddstream.games/gosol/util"
+)
+
+// CardPackNames returns the names of the card packs in the config directory
+func CardPackNames() []string {
+	return util.ConfigDirNames("cards")
+}
+
+// loadCardPack reads the images of a card pack, from a sprite sheet if it has a manifest,
+// otherwise from a file for each card
+func loadCardPack(name string) (map[string]image.Image, error) {
+	var dir string = "cards/" + name
+	if data := loadConfigFile(dir + "/manifest.json"); data != nil {
+		return loadCardPackSheet(dir, data)
+	}
+	var images map[string]image.Image = make(map[string]image.Image)
+	for _, fname := range util.ConfigFileNames(dir) {
+		var ext string = strings.ToLower(path.Ext(fname))
+		var card string = strings.TrimSuffix(path.Base(fname), path.Ext(fname))
+		if card != "back" && card != "movable" {
+			card = strings.ToUpper(card)
+		}
+		switch ext {
+		case ".png", ".jpg", ".jpeg":
+			img, _, err := image.Decode(bytes.NewReader(loadConfigFile(fname)))
+			if err != nil {
+				log.Println(fname, err)
+				continue
+			}
+			images[card] = img
+		case ".svg":
+			log.Println(fname, "cannot be read; SVG cards need converting to PNG")
+		}
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("There are no cards in %s", dir)
+	}
+	return images, nil
+}
+
+// loadCardPackSheet cuts the images of a card pack from a sprite sheet, as its manifest says
+func loadCardPackSheet(dir string, data []byte) (map[string]image.Image, error) {
+	var manifest cardPackManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%s/manifest.json: %s", dir, err)
+	}
+	var sheetBytes []byte = loadConfigFile(dir + "/" + manifest.Image)
+	if sheetBytes == nil {
+		return nil, fmt.Errorf("%s/manifest.json: cannot load %s", dir, manifest.Image)
+	}
+	sheet, _, err := image.Decode(bytes.NewReader(sheetBytes))
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s: %s", dir, manifest.Image, err)
+	}
+	sub, ok := sheet.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return nil, errors.New("Cannot cut cards from " + manifest.Image)
+	}
+	var images map[string]image.Image = make(map[string]image.Image)
+	for card, xywh := range manifest.Cards {
+		var r image.Rectangle
+		switch len(xywh) {
+		case 2:
+			r = image.Rect(xywh[0], xywh[1], xywh[0]+manifest.Width, xywh[1]+manifest.Height)
+		case 4:
+			r = image.Rect(xywh[0], xywh[1], xywh[0]+xywh[2], xywh[1]+xywh[3])
+		default:
+			return nil, fmt.Errorf("%s/manifest.json: %s should be x, y or x, y, width, height", dir, card)
+		}
+		if r.Empty() || !r.In(sheet.Bounds()) {
+			return nil, fmt.Errorf("%s/manifest.json: %s is not in %s", dir, card, manifest.Image)
+		}
+		images[card] = sub.SubImage(r)
+	}
+	return images, nil
+}
+
+// loadConfigFile returns the contents of a file in the config directory, or nil if it cannot be read
+func loadConfigFile(fname string) []byte {
+	bytes, count, err := util.LoadBytesFromFile(fname, false)
+	if err != nil || count == 0 {
+		return nil
+	}
+	return bytes[:count]
+}
@@ -0,0 +1,17 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"image"
+)
+
+// CardPackNames returns no card packs, as a browser has no config directory to put them in
+func CardPackNames() []string {
+	return nil
+}
+
+func loadCardPack(name string) (map[string]image.Image, error) {
+	return nil, errors.New("Card packs cannot be used in a browser")
+}
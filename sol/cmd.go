@@ -3,35 +3,34 @@ package sol
 import (
 	"fmt"
 	"log"
+	"strconv"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/core"
 	"oddstream.games/gosol/ui"
 )
 
 var CommandTable = map[ebiten.Key]func(){
 	ebiten.KeyN: func() { TheGame.Baize.NewDeal() },
 	ebiten.KeyR: func() { TheGame.Baize.RestartDeal() },
+	ebiten.KeyD: func() { ShowDealDrawer() },
 	ebiten.KeyU: func() { TheGame.Baize.Undo() },
+	ebiten.KeyY: func() { TheGame.Baize.Redo() },
+	ebiten.KeyT: func() { TheGame.Baize.ShowBranchDrawer() },
+	ebiten.KeyP: func() { ShowReplayPicker() },
+	ebiten.KeyW: func() { TheGame.Baize.Winnable() },
+	ebiten.KeyE: func() { TheGame.Baize.ExportPosition() },
 	ebiten.KeyB: func() {
 		if ebiten.IsKeyPressed(ebiten.KeyControl) {
-			TheGame.Baize.LoadPosition()
+			TheGame.Baize.ShowBookmarkDrawer()
 		} else {
-			TheGame.Baize.SavePosition()
+			TheGame.Baize.AddBookmark()
 		}
 	},
-	ebiten.KeyL: func() { TheGame.Baize.LoadPosition() },
-	ebiten.KeyS: func() { TheGame.Baize.SavePosition() },
+	ebiten.KeyL: func() { TheGame.Baize.ShowBookmarkDrawer() },
+	ebiten.KeyS: func() { TheGame.Baize.AddBookmark() },
 	ebiten.KeyC: func() { TheGame.Baize.Collect2() },
-	ebiten.KeyH: func() {
-		TheGame.Settings.ShowMovableCards = !TheGame.Settings.ShowMovableCards
-		if TheGame.Settings.ShowMovableCards {
-			if TheGame.Baize.moves+TheGame.Baize.fmoves > 0 {
-				TheGame.UI.ToastInfo("Movable cards highlighted")
-			} else {
-				TheGame.UI.ToastError("There are no movable cards")
-			}
-		}
-	},
+	ebiten.KeyH: func() { TheGame.Baize.Hint() },
 	ebiten.KeyM: func() {
 		TheGame.Settings.AlwaysShowMovableCards = !TheGame.Settings.AlwaysShowMovableCards
 		TheGame.Settings.ShowMovableCards = TheGame.Settings.AlwaysShowMovableCards
@@ -39,8 +38,10 @@ var CommandTable = map[ebiten.Key]func(){
 			TheGame.UI.ToastInfo("Movable cards always highlighted")
 		}
 	},
-	ebiten.KeyF: func() { TheGame.UI.ShowVariantPickerEx(VariantGroupNames(), "ShowVariantPicker") },
+	ebiten.KeyF: func() { TheGame.UI.ShowVariantPickerEx(core.VariantGroupNames(), "ShowVariantPicker") },
 	ebiten.KeyA: func() { ShowAniSpeedDrawer() },
+	ebiten.KeyK: func() { ShowThemePicker() },
+	ebiten.KeyG: func() { ShowCardPackPicker() },
 	ebiten.KeyX: func() { ExitRequested = true },
 	// ebiten.KeyTab: func() {
 	// 	if DebugMode {
@@ -52,40 +53,101 @@ var CommandTable = map[ebiten.Key]func(){
 	ebiten.KeyF1: func() { TheGame.Baize.Wikipedia() },
 	ebiten.KeyF2: func() { ShowStatisticsDrawer() },
 	ebiten.KeyF3: func() { ShowSettingsDrawer() },
+	ebiten.KeyF4: func() { TheGame.Baize.SaveNotation() },
 	ebiten.KeyF5: func() { TheGame.Baize.StartSpinning() }, // debug
 	ebiten.KeyF6: func() { TheGame.Baize.StopSpinning() },  // debug
 	ebiten.KeyF7: func() {
 		TheGame.UI.AddButtonToFAB("restore", ebiten.KeyR)
 		TheGame.UI.AddButtonToFAB("done_all", ebiten.KeyC)
 	}, // debug
-	ebiten.KeyF8:     func() { TheGame.UI.HideFAB() }, // debug
-	ebiten.KeyMenu:   func() { TheGame.UI.ToggleNavDrawer() },
-	ebiten.KeyEscape: func() { TheGame.UI.HideActiveDrawer() },
+	ebiten.KeyF8:    func() { TheGame.UI.HideFAB() }, // debug
+	ebiten.KeyMenu:  func() { TheGame.UI.ToggleNavDrawer() },
+	ebiten.KeyLeft:  func() { TheGame.Baize.CursorLeftRight(-1) },
+	ebiten.KeyRight: func() { TheGame.Baize.CursorLeftRight(1) },
+	ebiten.KeyUp:    func() { TheGame.Baize.CursorUpDown(-1) },
+	ebiten.KeyDown:  func() { TheGame.Baize.CursorUpDown(1) },
+	ebiten.KeyEnter: func() { TheGame.Baize.CursorSelect() },
+	ebiten.KeySpace: func() { TheGame.Baize.CursorSelect() },
+	ebiten.KeyTab:   func() { TheGame.Baize.CursorNextHome() },
+	ebiten.KeyEscape: func() {
+		TheGame.Baize.CursorCancel()
+		TheGame.UI.HideActiveDrawer()
+	},
 }
 
 func Execute(cmd interface{}) {
+	if k, ok := cmd.(ebiten.Key); ok && TheGame.UI.DrawerKey(k) {
+		return // moving about an open drawer, eg with a gamepad
+	}
 	TheGame.UI.HideActiveDrawer()
 	TheGame.UI.HideFAB()
 	switch v := cmd.(type) {
 	case ebiten.Key:
+		if TheGame.Baize.Replaying() {
+			if fn, ok := ReplayCommandTable[v]; ok {
+				fn()
+				if TheGame.Baize.Replaying() {
+					TheGame.Baize.showReplayControls()
+				}
+				return
+			}
+			TheGame.Baize.StopReplay()
+		}
 		if fn, ok := CommandTable[v]; ok {
 			fn()
 		}
 	case ui.Command:
 		// a widget has sent a command
+		if v.Command != "WatchReplay" {
+			TheGame.Baize.StopReplay()
+		}
 		switch v.Command {
 		case "ShowVariantGroupPicker":
-			TheGame.UI.ShowVariantPickerEx(VariantGroupNames(), "ShowVariantPicker")
+			TheGame.UI.ShowVariantPickerEx(core.VariantGroupNames(), "ShowVariantPicker")
 		case "ShowVariantPicker":
 			TheGame.UI.ShowVariantPickerEx(VariantNames(v.Data), "ChangeVariant")
 		case "ChangeVariant":
-			if _, ok := Variants[v.Data]; !ok {
+			if _, ok := core.Variants[v.Data]; !ok {
 				TheGame.UI.ToastError(fmt.Sprintf("Don't know how to play '%s'", v.Data))
-			} else if v.Data == TheGame.Baize.variant {
+			} else if v.Data == TheGame.Baize.core.Variant() {
 				TheGame.UI.ToastError(fmt.Sprintf("Already playing '%s'", v.Data))
 			} else {
 				TheGame.Baize.ChangeVariant(v.Data)
 			}
+		case "DealNumber":
+			if seed, err := strconv.ParseUint(v.Data, 10, 64); err != nil || seed == 0 {
+				TheGame.UI.ToastError(fmt.Sprintf("'%s' is not a deal number", v.Data))
+			} else {
+				var shuffle core.ShuffleType = core.SHUFFLE_GOSOL
+				if TheGame.Settings.MicrosoftDeals && TheGame.Baize.core.Script().MicrosoftDeals() {
+					shuffle = core.SHUFFLE_MICROSOFT
+				}
+				TheGame.Baize.NewDealWithSeed(seed, shuffle)
+			}
+		case "GotoBranch":
+			// the branches are listed as "1. 23 moves, 45%"
+			var n int
+			if _, err := fmt.Sscanf(v.Data, "%d.", &n); err != nil {
+				log.Println("bad branch", v.Data)
+			} else {
+				TheGame.Baize.GotoBranch(n - 1)
+			}
+		case "GotoBookmark":
+			TheGame.Baize.GotoBookmark(v.Data)
+		case "DeleteBookmark":
+			TheGame.Baize.DeleteBookmark(v.Data)
+		case "WatchReplay":
+			if v.Data == thisGame {
+				TheGame.Baize.WatchReplay(TheGame.Baize.game().Replay())
+			} else if r, err := LoadReplay(v.Data); err != nil {
+				TheGame.UI.ToastError(fmt.Sprintf("Could not load %s: %s", v.Data, err))
+			} else {
+				TheGame.Baize.WatchReplay(r)
+			}
+		case "ChangeTheme":
+			TheGame.Baize.ChangeTheme(v.Data)
+		case "ChangeCardPack":
+			TheGame.Baize.ChangeCardPack(v.Data)
 		case "SaveSettings":
 			TheGame.Settings.Save() // save now especially if running in a browser
 		default:
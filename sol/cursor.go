@@ -0,0 +1,251 @@
+package sol
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"oddstream.games/gosol/core"
+	"oddstream.games/gosol/sound"
+	"oddstream.games/gosol/util"
+)
+
+// cursor is where the keyboard is pointing, so the game can be played without a mouse;
+// the arrow keys move it between piles and cards, Enter or Space picks up the tail of cards
+// under it, and Enter or Space again puts the tail down on the pile it has been moved to
+type cursor struct {
+	visible bool         // shown after an arrow key is pressed, hidden when the mouse is used
+	pile    int          // index into Baize.piles
+	card    int          // index into the cards of the pile
+	tail    []*Card      // the cards picked up, if any
+	homes   []*core.Pile // where the tail can go
+}
+
+// cursorPile returns the pile the cursor is on, or nil if there are no piles to be on
+func (b *Baize) cursorPile() *Pile {
+	if b.cursor.pile < 0 || b.cursor.pile >= len(b.piles) || b.piles[b.cursor.pile].Hidden() {
+		b.cursor.pile = -1
+		for i, p := range b.piles {
+			if !p.Hidden() {
+				b.cursor.pile = i
+				break
+			}
+		}
+		if b.cursor.pile == -1 {
+			return nil
+		}
+		b.cursor.card = b.piles[b.cursor.pile].Len() - 1
+	}
+	return b.piles[b.cursor.pile]
+}
+
+// cursorCard returns the card the cursor is on, or nil if its pile is empty
+func (b *Baize) cursorCard() *Card {
+	var p *Pile = b.cursorPile()
+	if p == nil || p.Len() == 0 {
+		return nil
+	}
+	if b.cursor.card < 0 || b.cursor.card >= p.Len() {
+		b.cursor.card = p.Len() - 1
+	}
+	return p.cards[b.cursor.card]
+}
+
+// moveCursorTo puts the cursor on the top card of the i'th pile
+func (b *Baize) moveCursorTo(i int) {
+	b.cursor.pile = i
+	b.cursor.card = b.piles[i].Len() - 1
+}
+
+// CursorLeftRight moves the cursor to the next pile in the same row, to the left (dx -1) or right (dx 1),
+// going round to the other end of the row
+func (b *Baize) CursorLeftRight(dx int) {
+	if !b.showCursor() {
+		return
+	}
+	var here image.Point = b.piles[b.cursor.pile].Slot()
+	var best, wrap int = -1, -1
+	for i, p := range b.piles {
+		var slot image.Point = p.Slot()
+		if p.Hidden() || slot.Y != here.Y || i == b.cursor.pile {
+			continue
+		}
+		var d int = (slot.X - here.X) * dx
+		if d > 0 && (best == -1 || d < (b.piles[best].Slot().X-here.X)*dx) {
+			best = i
+		}
+		if d < 0 && (wrap == -1 || d < (b.piles[wrap].Slot().X-here.X)*dx) {
+			wrap = i
+		}
+	}
+	if best == -1 {
+		best = wrap
+	}
+	if best != -1 {
+		b.moveCursorTo(best)
+	}
+	b.announceCursor()
+}
+
+// CursorUpDown moves the cursor up (dy -1) or down (dy 1) the face up cards of a pile,
+// then on to the nearest pile in the row above or below
+func (b *Baize) CursorUpDown(dy int) {
+	if !b.showCursor() {
+		return
+	}
+	var p *Pile = b.piles[b.cursor.pile]
+	if b.cursorCard() != nil {
+		var next int = b.cursor.card + dy
+		if next >= 0 && next < p.Len() && !p.cards[next].Prone() {
+			b.cursor.card = next
+			b.announceCursor()
+			return
+		}
+	}
+	var here image.Point = p.Slot()
+	var best int = -1
+	var bestRow, bestCol int
+	for i, q := range b.piles {
+		var slot image.Point = q.Slot()
+		if q.Hidden() || (slot.Y-here.Y)*dy <= 0 {
+			continue
+		}
+		var row, col int = (slot.Y - here.Y) * dy, util.Abs(slot.X - here.X)
+		if best == -1 || row < bestRow || (row == bestRow && col < bestCol) {
+			best, bestRow, bestCol = i, row, col
+		}
+	}
+	if best != -1 {
+		b.moveCursorTo(best)
+	}
+	b.announceCursor()
+}
+
+// showCursor shows the cursor, returning false if it was hidden, so the first key press only shows it
+func (b *Baize) showCursor() bool {
+	if b.cursorPile() == nil {
+		return false
+	}
+	if !b.cursor.visible {
+		b.cursor.visible = true
+		b.announceCursor()
+		return false
+	}
+	return true
+}
+
+// CursorSelect picks up the tail of cards under the cursor, or puts down the tail picked up,
+// with the same checks as dragging it with the mouse; stocks, and face down cards, are tapped instead
+func (b *Baize) CursorSelect() {
+	if !b.showCursor() && b.cursor.tail == nil {
+		return
+	}
+	var p *Pile = b.cursorPile()
+	if b.cursor.tail != nil {
+		b.dropCursorTail(p)
+		return
+	}
+	var c *Card = b.cursorCard()
+	if p.IsStock() || c == nil || c.Prone() {
+		var crc uint32 = b.core.CRC()
+		if c == nil {
+			b.core.PileTapped(p.core)
+		} else {
+			b.core.TailTapped(coreTail([]*Card{p.Peek()}))
+		}
+		if crc != b.core.CRC() {
+			sound.Play("Slide")
+			b.AfterUserMove()
+			b.cursor.card = p.Len() - 1
+			b.announceCursor()
+		}
+		return
+	}
+	var tail []*Card = p.MakeTail(c)
+	if ok, err := p.core.CanMoveTail(coreTail(tail)); !ok {
+		TheGame.UI.ToastError(err.Error())
+		return
+	}
+	b.cursor.tail = tail
+	b.cursor.homes = b.core.FindHomesForTail(coreTail(tail))
+	b.CursorNextHome()
+	b.announcePickUp()
+}
+
+// CursorNextHome moves the cursor to the next place the tail picked up can go
+func (b *Baize) CursorNextHome() {
+	if len(b.cursor.homes) == 0 {
+		return
+	}
+	var next *core.Pile = b.cursor.homes[0]
+	for i, h := range b.cursor.homes {
+		if b.piles[b.cursor.pile].core == h {
+			next = b.cursor.homes[(i+1)%len(b.cursor.homes)]
+			break
+		}
+	}
+	for i, p := range b.piles {
+		if p.core == next {
+			b.moveCursorTo(i)
+		}
+	}
+	b.announceCursor()
+}
+
+// dropCursorTail puts the tail picked up on dst, if the rules allow it
+func (b *Baize) dropCursorTail(dst *Pile) {
+	var tail []*Card = b.cursor.tail
+	b.CursorCancel()
+	if dst == tail[0].Owner() {
+		return
+	}
+	var crc uint32 = b.core.CRC()
+	var spoken string = announceTail(tail)
+	if err := b.core.MoveTailTo(coreTail(tail), dst.core); err != nil {
+		TheGame.UI.ToastError(err.Error())
+	} else if crc != b.core.CRC() {
+		sound.Play("Place")
+		b.announceMove(spoken, dst)
+		b.AfterUserMove()
+		b.cursor.card = dst.Len() - 1
+	}
+}
+
+// CursorCancel puts the tail picked up back down where it was
+func (b *Baize) CursorCancel() {
+	b.cursor.tail = nil
+	b.cursor.homes = nil
+}
+
+// hideCursor hides the cursor when the mouse is used
+func (b *Baize) hideCursor() {
+	b.cursor.visible = false
+	b.CursorCancel()
+}
+
+// drawCursor outlines the card (or empty pile) under the cursor, the tail picked up, and where it can go
+func (b *Baize) drawCursor(screen *ebiten.Image) {
+	if !b.cursor.visible || b.cursorPile() == nil {
+		return
+	}
+	var outline = func(r image.Rectangle, width float32, clr color.Color) {
+		r = r.Add(b.dragOffset)
+		vector.StrokeRect(screen, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), width, clr, true)
+	}
+	for _, h := range b.cursor.homes {
+		for _, p := range b.piles {
+			if p.core == h {
+				outline(p.FannedBaizeRect(), 2, ExtendedColors["LightGreen"])
+			}
+		}
+	}
+	if len(b.cursor.tail) > 0 {
+		outline(b.cursor.tail[0].BaizeRect().Union(b.cursor.tail[len(b.cursor.tail)-1].BaizeRect()), 2, ExtendedColors["White"])
+	}
+	if c := b.cursorCard(); c != nil {
+		outline(c.BaizeRect(), 4, ExtendedColors["Gold"])
+	} else {
+		outline(b.cursorPile().BaizeRect(), 4, ExtendedColors["Gold"])
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/core"
 	"oddstream.games/gosol/sound"
 	"oddstream.games/gosol/ui"
 )
@@ -61,6 +62,8 @@ var (
 	MovableCardBackImage *ebiten.Image
 	// CardShadowImage applies to all cards so is kept globally as an optimization
 	CardShadowImage *ebiten.Image
+	// JokerFaceImage is the face of every joker
+	JokerFaceImage *ebiten.Image
 	// ExitRequested is set when user has had enough
 	ExitRequested bool = false
 )
@@ -69,6 +72,7 @@ var TheGame *Game // pointer to object that implements ebiten.Game interface
 
 // NewGame generates a new Game object, which implements ebiten.Game interface
 func NewGame() {
+	core.DebugMode = DebugMode
 	TheGame = &Game{Settings: NewSettings()}
 	if TheGame.Settings.Mute {
 		sound.SetVolume(0.0)
@@ -77,6 +81,8 @@ func NewGame() {
 	}
 	TheGame.Statistics = NewStatistics()
 	TheGame.UI = ui.New(Execute)
+	TheGame.UI.SetAnnouncer(Announce)
+	LoadVariants()
 	if TheGame.Baize = NewBaize(TheGame.Settings.Variant); TheGame.Baize == nil {
 		log.Panic("cannot create Baize")
 	}
@@ -4,8 +4,14 @@ package sol
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"oddstream.games/gosol/core"
 	"oddstream.games/gosol/util"
 )
 
@@ -67,25 +73,19 @@ func (s *Statistics) Save() {
 	util.SaveBytesToFile(bytes, "statistics.json")
 }
 
-// Load an undo stack saved to json
+// Load a game saved to json
 func (b *Baize) Load() {
-	bytes, count, err := util.LoadBytesFromFile("saved."+b.variant+".json", true)
+	bytes, count, err := util.LoadBytesFromFile("saved."+b.core.Variant()+".json", true)
 	if err != nil || count == 0 || bytes == nil {
 		return
 	}
-	var undoStack []*SavableBaize
 	// golang gotcha reslice buffer to number of bytes actually read
-	err = json.Unmarshal(bytes[:count], &undoStack)
-	if err != nil {
+	if err := b.SetGame(bytes[:count]); err != nil {
 		log.Fatal(err)
 	}
-	if !b.isSavableStackOk(undoStack) {
-		log.Fatal("saved undo stack is not ok")
-	}
-	b.SetUndoStack(undoStack)
 }
 
-// Save the entire undo stack to file
+// Save the game, with its history, to file
 func (b *Baize) Save() {
 	// defer util.Duration(time.Now(), "Baize.Save")
 
@@ -94,10 +94,153 @@ func (b *Baize) Save() {
 	// 	return
 	// }
 
-	bytes, err := json.MarshalIndent(b.undoStack, "", "\t")
+	bytes, err := b.game().MarshalGame()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	util.SaveBytesToFile(bytes, "saved."+b.variant+".json")
+	util.SaveBytesToFile(bytes, "saved."+b.game().Variant()+".json")
+}
+
+// SaveReplay writes the moves of the game to a file in the replays config directory,
+// named after the variant, the deal and the time, so it can be watched again or shared
+func (b *Baize) SaveReplay() {
+	bytes, err := b.game().Replay().Marshal()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	var name string = fmt.Sprintf("%s %s %s", b.game().Variant(), b.DealNumber(), time.Now().Format("2006-01-02 15.04"))
+	util.SaveBytesToFile(bytes, "replays/"+strings.Join(strings.Fields(name), " ")+".json")
+}
+
+// SaveNotation writes the game, from the deal, as text to position.txt in the config directory,
+// to be pasted into a bug report
+func (b *Baize) SaveNotation() {
+	text, err := core.FormatGame(b.game().UndoStack())
+	if err != nil {
+		TheGame.UI.ToastError(err.Error())
+		return
+	}
+	text = fmt.Sprintf("# %s deal %s\n", b.game().Variant(), b.DealNumber()) + text
+	util.SaveBytesToFile([]byte(text), "position.txt")
+	TheGame.UI.ToastInfo("Game written to position.txt")
+}
+
+// ExportPosition writes the current position to the config directory, in each of the formats
+// other solvers read that it can be written in
+func (b *Baize) ExportPosition() {
+	var fnames []string
+	var err error
+	for _, format := range []string{core.FormatFCSolve, core.FormatPySol} {
+		var text string
+		if text, err = core.ExportPosition(b.game().Position(), format); err != nil {
+			continue
+		}
+		var fname string = "position." + format + ".txt"
+		util.SaveBytesToFile([]byte(text), fname)
+		fnames = append(fnames, fname)
+	}
+	if len(fnames) == 0 {
+		TheGame.UI.ToastError("Cannot export this position: " + err.Error())
+		return
+	}
+	TheGame.UI.ToastInfo("Position written to " + strings.Join(fnames, " and "))
+}
+
+// ImportPosition starts a game of the current variant from a position in fc-solve or PySol format
+func (b *Baize) ImportPosition(fname string) {
+	bytes, err := os.ReadFile(fname)
+	if err == nil {
+		err = b.core.ImportPosition(string(bytes))
+	}
+	if err != nil {
+		TheGame.UI.ToastError(fmt.Sprintf("Could not import %s: %s", fname, err))
+		return
+	}
+	b.StopSpinning()
+	TheGame.UI.ToastInfo("Imported " + fname)
+	b.dirtyFlags = 0xFFFF
+	b.refresh()
+}
+
+// ExportSavedGame prints the position of the saved game of the last variant played, without opening a window
+func ExportSavedGame(format string) error {
+	var settings *Settings = NewSettings()
+	loadVariants(func(string, error) {}) // the last variant played may be one of the player's own; mistakes are logged
+	cb := core.NewBaize(settings.Variant, &settings.Options)
+	if cb == nil {
+		return fmt.Errorf("Do not know how to play %s", settings.Variant)
+	}
+	cb.StartFreshGame(core.NewSeed())
+	bytes, count, err := util.LoadBytesFromFile("saved."+settings.Variant+".json", false)
+	if err != nil {
+		return err
+	}
+	// golang gotcha reslice buffer to number of bytes actually read
+	if err := cb.UnmarshalGame(bytes[:count]); err != nil {
+		return err
+	}
+	text, err := core.ExportPosition(cb.Position(), format)
+	if err != nil {
+		return err
+	}
+	fmt.Print(text)
+	return nil
+}
+
+// ReplayNames returns the names of the replays in the replays config directory
+func ReplayNames() []string {
+	var names []string
+	for _, fname := range util.ConfigFileNames("replays") {
+		if strings.HasSuffix(fname, ".json") {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(fname, "replays/"), ".json"))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadReplay reads a replay from the replays config directory
+func LoadReplay(name string) (*core.Replay, error) {
+	bytes, count, err := util.LoadBytesFromFile("replays/"+name+".json", false)
+	if err != nil {
+		return nil, err
+	}
+	// golang gotcha reslice buffer to number of bytes actually read
+	return core.ParseReplay(bytes[:count])
+}
+
+// LoadVariants adds the variants described by the .json files,
+// and written in the .lua files, in the variants config directory
+func LoadVariants() {
+	loadVariants(func(fname string, err error) {
+		TheGame.UI.Toast("Error", fmt.Sprintf("Could not load %s: %s", fname, err))
+	})
+}
+
+// loadVariants adds the player's own variants, logging the files that could not be loaded and telling failed about them
+func loadVariants(failed func(fname string, err error)) {
+	for _, fname := range util.ConfigFileNames("variants") {
+		if !strings.HasSuffix(fname, ".json") && !strings.HasSuffix(fname, ".lua") {
+			continue
+		}
+		bytes, count, err := util.LoadBytesFromFile(fname, false)
+		if err != nil || count == 0 || bytes == nil {
+			continue
+		}
+		// golang gotcha reslice buffer to number of bytes actually read
+		if strings.HasSuffix(fname, ".lua") {
+			err = core.AddLua(string(bytes[:count]))
+		} else {
+			var def *core.VariantDefinition
+			if def, err = core.ParseVariant(bytes[:count]); err == nil {
+				err = core.AddVariant(def)
+			}
+		}
+		if err != nil {
+			log.Println(fname, err)
+			failed(fname, err)
+		}
+	}
 }
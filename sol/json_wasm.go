@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"log"
 	"syscall/js"
+
+	"oddstream.games/gosol/core"
 )
 
 const keyPrefix = "gosol/"
@@ -93,21 +95,14 @@ func (s *Statistics) Save() {
 
 // Load the entire undo stack from storage
 func (b *Baize) Load() {
-	bytes, err := loadBytesFromLocalStorage("saved."+b.variant, true)
+	bytes, err := loadBytesFromLocalStorage("saved."+b.core.Variant(), true)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	var undoStack []*SavableBaize
-	err = json.Unmarshal(bytes, undoStack)
-	if err != nil {
-		log.Println("%s.Load().Unmarshal() error", b.variant, err)
-		return
+	if err := b.SetGame(bytes); err != nil {
+		log.Printf("%s.Load() error %v", b.core.Variant(), err)
 	}
-	if !b.IsSavableStackOk(undoStack) {
-		log.Println("saved undo stack is not ok")
-	}
-	b.SetUndoStack(undoStack)
 }
 
 // Save the entire undo stack to storage
@@ -116,10 +111,56 @@ func (b *Baize) Save() {
 	// if len(b.undoStack) < 2 || b.Complete() {
 	// 	return
 	// }
-	bytes, err := json.Marshal(b.undoStack)
+	bytes, err := b.game().MarshalGame()
 	if err != nil {
 		log.Println("Baize.Save().Marshal() error", err)
 	} else {
-		saveBytesToLocalStorage(bytes, "saved."+b.variant)
+		saveBytesToLocalStorage(bytes, "saved."+b.game().Variant())
 	}
 }
+
+// LoadVariants does nothing, because there is nowhere to put variant files in a browser
+func LoadVariants() {}
+
+// SaveReplay does nothing, because there is nowhere to put replay files in a browser;
+// the game being played can still be replayed
+func (b *Baize) SaveReplay() {}
+
+// SaveNotation writes the game, from the deal, as text to the browser console, because there is nowhere to put a file
+func (b *Baize) SaveNotation() {
+	text, err := core.FormatGame(b.game().UndoStack())
+	if err != nil {
+		TheGame.UI.ToastError(err.Error())
+		return
+	}
+	log.Printf("# %s deal %s\n%s", b.game().Variant(), b.DealNumber(), text)
+	TheGame.UI.ToastInfo("Game written to the browser console")
+}
+
+// ExportPosition writes the current position to the browser console, in each of the formats
+// other solvers read that it can be written in, because there is nowhere to put a file
+func (b *Baize) ExportPosition() {
+	var err error
+	var exported bool
+	for _, format := range []string{core.FormatFCSolve, core.FormatPySol} {
+		var text string
+		if text, err = core.ExportPosition(b.game().Position(), format); err != nil {
+			continue
+		}
+		log.Printf("# %s\n%s", format, text)
+		exported = true
+	}
+	if !exported {
+		TheGame.UI.ToastError("Cannot export this position: " + err.Error())
+		return
+	}
+	TheGame.UI.ToastInfo("Position written to the browser console")
+}
+
+// ReplayNames returns nothing, because there are no replay files in a browser
+func ReplayNames() []string { return nil }
+
+// LoadReplay fails, because there are no replay files in a browser
+func LoadReplay(name string) (*core.Replay, error) {
+	return nil, fmt.Errorf("no replay called %s", name)
+}
@@ -1,40 +1,16 @@
 package sol
 
-//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
 //lint:file-ignore ST1006 Receiver name will be anything I like, thank you
 
 import (
-	"errors"
-	"fmt"
 	"image"
+	"image/color"
 	"log"
-	"math/rand"
-	"time"
 
+	"github.com/fogleman/gg"
 	"github.com/hajimehoshi/ebiten/v2"
-	"oddstream.games/gosol/cardid"
-)
-
-type FanType int
-
-const (
-	FAN_NONE FanType = iota
-	FAN_DOWN
-	FAN_LEFT
-	FAN_RIGHT
-	FAN_DOWN3
-	FAN_LEFT3
-	FAN_RIGHT3
-)
-
-type MoveType int
-
-const (
-	MOVE_NONE MoveType = iota
-	MOVE_ANY
-	MOVE_ONE
-	MOVE_ONE_PLUS
-	MOVE_ONE_OR_ALL
+	"oddstream.games/gosol/core"
+	"oddstream.games/gosol/schriftbank"
 )
 
 const (
@@ -43,6 +19,12 @@ const (
 	CARD_BACK_FAN_FACTOR   = 8
 )
 
+const (
+	// https://en.wikipedia.org/wiki/Miscellaneous_Symbols
+	RECYCLE_RUNE   = rune(0x267B)
+	NORECYCLE_RUNE = rune(0x2613)
+)
+
 var DefaultFanFactor [7]float64 = [7]float64{
 	1.0,                    // FAN_NONE
 	CARD_FACE_FAN_FACTOR_V, // FAN_DOWN
@@ -53,205 +35,45 @@ var DefaultFanFactor [7]float64 = [7]float64{
 	CARD_FACE_FAN_FACTOR_H, // FAN_RIGHT3,
 }
 
-// MovableTail is used for collecting tap destinations
-type MovableTail struct {
-	dst  *Pile
-	tail []*Card
-}
-
-// PileVtabler interface for each subpile type, implements the behaviours
-// specific to each subtype
-type PileVtabler interface {
-	CanAcceptTail([]*Card) (bool, error)
-	TailTapped([]*Card)
-	Conformant() bool
-	UnsortedPairs() int
-	MovableTails() []*MovableTail
-	Placeholder() *ebiten.Image
-}
-
-// Pile is a generic container for cards
+// Pile is the picture of a core.Pile; it knows where the pile is on the baize,
+// and holds the Cards that picture the cards in the core.Pile, in the same order
 type Pile struct {
-	category  string
-	vtable    PileVtabler
-	label     string
-	moveType  MoveType
-	fanType   FanType
-	cards     []*Card
-	slot      image.Point // logical position on baize
+	core      *core.Pile
+	cards     []*Card     // set by Baize.syncCards
 	pos       image.Point // actual position on baize
 	pos1      image.Point // waste pos #1
 	pos2      image.Point // waste pos #1
 	fanFactor float64
-	// buddyPos    image.Point
-	img *ebiten.Image
-	// target bool // experimental, might delete later, IDK
+	img       *ebiten.Image
+	label     string // label that img was drawn with
 }
 
-func NewPile(category string, slot image.Point, fanType FanType, moveType MoveType) *Pile {
-	var p *Pile = &Pile{
-		category:  category,
-		moveType:  moveType,
-		slot:      slot,
-		fanType:   fanType,
-		fanFactor: DefaultFanFactor[fanType],
-	}
-	TheGame.Baize.AddPile(p)
-	return p
-}
-
-// func (self *Pile) Valid() bool {
-// 	return self != nil
-// }
-
-func (self *Pile) Reset() {
-	self.cards = self.cards[:0]
-	self.fanFactor = DefaultFanFactor[self.fanType]
+func NewPile(cp *core.Pile) *Pile {
+	return &Pile{core: cp, fanFactor: DefaultFanFactor[cp.FanType()]}
 }
 
 // Hidden returns true if this pile is off screen
 func (self *Pile) Hidden() bool {
-	return self.slot.X < 0 || self.slot.Y < 0
+	return self.core.Hidden()
 }
 
-// func (self *Pile) IsCell() bool {
-// 	_, ok := self.vtable.(*Cell)
-// 	return ok
-// }
-
-// func (self *Pile) IsFoundation() bool {
-// 	_, ok := self.vtable.(*Foundation)
-// 	return ok
-// }
-
 func (self *Pile) IsStock() bool {
-	// using a type assertion seems more idiomatic than a string comparison
-	_, ok := self.vtable.(*Stock)
-	return ok
-	// return self.category == "Stock"
-}
-
-// Fill this pile with a new set of cards. Returns the number of cards added.
-func (self *Pile) Fill(packs, suits int) int {
-	var count int = packs * suits * 13
-
-	self.cards = make([]*Card, 0, count)
-
-	for pack := 0; pack < packs; pack++ {
-		for suit := 0; suit < suits; suit++ {
-			for ord := 1; ord < 14; ord++ {
-				// suits are numbered NOSUIT=0, CLUB=1, DIAMOND=2, HEART=3, SPADE=4
-				// (i.e. not 0..3)
-				// run the suits loop backwards, so spades are used first
-				// (folks expect Spider One Suit to use spades)
-				var c Card = NewCard(pack, cardid.SPADE-suit, ord, self.pos)
-				self.Push(&c)
-			}
-		}
-	}
-
-	return count
-}
-
-func (self *Pile) Shuffle() {
-	rand.Seed(time.Now().UTC().UnixNano())
-	rand.Shuffle(self.Len(), self.Swap)
-	log.Printf("Shuffled %d cards", self.Len())
-}
-
-func (self *Pile) Cards() []*Card {
-	return self.cards
-}
-
-// Deprecated: not needed in new model
-func (self *Pile) FanType() FanType {
-	return self.fanType
+	return self.core.IsStock()
 }
 
-// Deprecated: not needed in new model
-func (self *Pile) SetFanType(fanType FanType) {
-	self.fanType = fanType
-}
-
-// Deprecated: not needed in new model
-func (self *Pile) MoveType() MoveType {
-	return self.moveType
+func (self *Pile) Slot() image.Point {
+	return self.core.Slot()
 }
 
-// Deprecated: not needed in new model
-func (self *Pile) Label() string {
-	return self.label
-}
-
-func (self *Pile) SetLabel(label string) {
-	if self.label != label {
-		self.label = label
-		TheGame.Baize.setFlag(dirtyPileBackgrounds)
-	}
-}
-
-// Deprecated: not needed in new model
-// func (self *Pile) Target() bool {
-// 	return self.target
-// }
-
-// Deprecated: not needed in new model
-// func (self *Pile) SetTarget(target bool) {
-// 	self.target = target
-// }
-
-// Empty returns true if this pile is empty (has no cards).
-func (self *Pile) Empty() bool {
-	return len(self.cards) == 0
+func (self *Pile) FanType() core.FanType {
+	return self.core.FanType()
 }
 
 // Len returns the number of cards in this pile.
-// Len satisfies the sort.Interface interface.
 func (self *Pile) Len() int {
 	return len(self.cards)
 }
 
-// Less satisfies the sort.Interface interface
-func (self *Pile) Less(i, j int) bool {
-	c1 := self.cards[i]
-	c2 := self.cards[j]
-	return c1.Suit() < c2.Suit() && c1.Ordinal() < c2.Ordinal()
-}
-
-// Swap satisfies the sort.Interface interface
-func (self *Pile) Swap(i, j int) {
-	self.cards[i], self.cards[j] = self.cards[j], self.cards[i]
-}
-
-// Get a *Card from this pile
-func (self *Pile) Get(i int) *Card {
-	return self.cards[i]
-}
-
-// Append a *Card to this pile
-func (self *Pile) Append(c *Card) {
-	self.cards = append(self.cards, c)
-}
-
-// Delete a *Card from this pile
-func (self *Pile) Delete(index int) {
-	self.cards = append(self.cards[:index], self.cards[index+1:]...)
-}
-
-// Extract a specific *Card from this pile
-func (self *Pile) Extract(pack, ordinal, suit int) *Card {
-	var ID cardid.CardID = cardid.NewCardID(pack, suit, ordinal)
-	for i, c := range self.cards {
-		if cardid.SameCardAndPack(ID, c.id) {
-			self.Delete(i)
-			c.FlipUp()
-			return c
-		}
-	}
-	log.Printf("Could not find card %d %d in %s", suit, ordinal, self.category)
-	return nil
-}
-
 // Peek topmost Card of this Pile (a stack)
 func (self *Pile) Peek() *Card {
 	if len(self.cards) == 0 {
@@ -260,101 +82,22 @@ func (self *Pile) Peek() *Card {
 	return self.cards[len(self.cards)-1]
 }
 
-// Pop a Card off the end of this Pile (a stack)
-func (self *Pile) Pop() *Card {
-	if len(self.cards) == 0 {
-		return nil
-	}
-	c := self.cards[len(self.cards)-1]
-	self.cards = self.cards[:len(self.cards)-1]
-	c.SetOwner(nil)
-	c.FlipUp()
-	TheGame.Baize.setFlag(dirtyCardPositions)
-	return c
-}
-
-// Push a Card onto the end of this Pile (a stack)
-func (self *Pile) Push(c *Card) {
-	var pos image.Point
-	if len(self.cards) == 0 {
-		pos = self.pos
-	} else {
-		pos = self.PosAfter(self.Peek()) // get this BEFORE appending card
-	}
-
-	self.cards = append(self.cards, c)
-	c.SetOwner(self)
-	c.LerpTo(pos)
-
-	if self.IsStock() {
-		c.FlipDown() // see? cards can transition and flip at the same time
-	}
-	TheGame.Baize.setFlag(dirtyCardPositions)
-}
-
-func (self *Pile) FlipUpExposedCard() {
-	if !self.IsStock() {
-		if c := self.Peek(); c != nil {
-			c.FlipUp()
-		}
-	}
-}
-
-func (self *Pile) ReverseCards() {
-	for i, j := 0, len(self.cards)-1; i < j; i, j = i+1, j-1 {
-		self.cards[i], self.cards[j] = self.cards[j], self.cards[i]
-	}
-}
-
-// BuryCards moves cards with the specified ordinal to the beginning of the pile
-func (self *Pile) BuryCards(ordinal int) {
-	tmp := make([]*Card, 0, cap(self.cards))
-	for _, c := range self.cards {
-		if c.Ordinal() == ordinal {
-			tmp = append(tmp, c)
-		}
-	}
-	for _, c := range self.cards {
-		if c.Ordinal() != ordinal {
-			tmp = append(tmp, c)
-		}
-	}
-	self.Reset()
-	for i := 0; i < len(tmp); i++ {
-		self.Push(tmp[i])
-	}
-	self.Refan()
-	// nb the card owner does not change
-}
-
-// Slot returns the virtual slot this pile is positioned at
-// TODO to use fractional slots, scale the slot values up by, say, 10.
-// Deprecated: not needed in new model
-func (self *Pile) Slot() image.Point {
-	return self.slot
-}
-
-// Deprecated: not needed in new model
-func (self *Pile) SetSlot(slot image.Point) {
-	self.slot = slot
-}
-
 // SetBaizePos sets the position of this Pile in Baize coords,
 // and also sets the auxillary waste pile fanned positions
 func (self *Pile) SetBaizePos(pos image.Point) {
 	self.pos = pos
-	switch self.fanType {
-	case FAN_DOWN3:
+	switch self.FanType() {
+	case core.FAN_DOWN3:
 		self.pos1.X = self.pos.X
 		self.pos1.Y = self.pos.Y + int(float64(CardHeight)/CARD_FACE_FAN_FACTOR_V)
 		self.pos2.X = self.pos.X
 		self.pos2.Y = self.pos1.Y + int(float64(CardHeight)/CARD_FACE_FAN_FACTOR_V)
-	case FAN_LEFT3:
+	case core.FAN_LEFT3:
 		self.pos1.X = self.pos.X - int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
 		self.pos1.Y = self.pos.Y
 		self.pos2.X = self.pos1.X - int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
 		self.pos2.Y = self.pos.Y
-	case FAN_RIGHT3:
+	case core.FAN_RIGHT3:
 		self.pos1.X = self.pos.X + int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
 		self.pos1.Y = self.pos.Y
 		self.pos2.X = self.pos1.X + int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
@@ -388,18 +131,15 @@ func (self *Pile) FannedBaizeRect() image.Rectangle {
 	var r image.Rectangle = self.BaizeRect()
 	if len(self.cards) > 1 {
 		var c *Card = self.Peek()
-		// if c.Dragging() {
-		// 	return r
-		// }
 		var cPos = c.BaizePos()
-		switch self.fanType {
-		case FAN_NONE:
+		switch self.FanType() {
+		case core.FAN_NONE:
 			// do nothing
-		case FAN_RIGHT, FAN_RIGHT3:
+		case core.FAN_RIGHT, core.FAN_RIGHT3:
 			r.Max.X = cPos.X + CardWidth
-		case FAN_LEFT, FAN_LEFT3:
+		case core.FAN_LEFT, core.FAN_LEFT3:
 			r.Max.X = cPos.X - CardWidth
-		case FAN_DOWN, FAN_DOWN3:
+		case core.FAN_DOWN, core.FAN_DOWN3:
 			r.Max.Y = cPos.Y + CardHeight
 		}
 	}
@@ -428,50 +168,29 @@ func (self *Pile) PosAfter(c *Card) image.Point {
 	if pos.X <= 0 && pos.Y <= 0 {
 		// the card is still at 0,0 where it started life
 		// and is yet to have pos calculated from the pile slot
-		// println("zero pos in PosAfter", self.category)
 		return pos
 	}
-	switch self.fanType {
-	case FAN_NONE:
+	switch self.FanType() {
+	case core.FAN_NONE:
 		// nothing to do
-	case FAN_DOWN:
+	case core.FAN_DOWN:
 		if c.Prone() {
 			pos.Y += int(float64(CardHeight) / float64(CARD_BACK_FAN_FACTOR))
 		} else {
 			pos.Y += int(float64(CardHeight) / self.fanFactor)
 		}
-	case FAN_LEFT:
+	case core.FAN_LEFT:
 		if c.Prone() {
 			pos.X -= int(float64(CardWidth) / float64(CARD_BACK_FAN_FACTOR))
 		} else {
 			pos.X -= int(float64(CardWidth) / self.fanFactor)
 		}
-	case FAN_RIGHT:
+	case core.FAN_RIGHT:
 		if c.Prone() {
 			pos.X += int(float64(CardWidth) / float64(CARD_BACK_FAN_FACTOR))
 		} else {
 			pos.X += int(float64(CardWidth) / self.fanFactor)
 		}
-	case FAN_DOWN3, FAN_LEFT3, FAN_RIGHT3:
-		switch len(self.cards) {
-		case 0:
-			// nothing to do
-		case 1:
-			pos = self.pos1 // incoming card at slot 1
-		case 2:
-			pos = self.pos2 // incoming card at slot 2
-		default:
-			pos = self.pos2 // incoming card at slot 2
-			// top card needs to transition from slot[2] to slot[1]
-			i := len(self.cards) - 1
-			self.cards[i].LerpTo(self.pos1)
-			// mid card needs to transition from slot[1] to slot[0]
-			// all other cards to slot[0]
-			for i > 0 {
-				i--
-				self.cards[i].LerpTo(self.pos)
-			}
-		}
 	}
 	return pos
 }
@@ -479,17 +198,17 @@ func (self *Pile) PosAfter(c *Card) image.Point {
 func (self *Pile) Refan() {
 	// TODO trying set pos instead of transition
 	var doFan3 bool = false
-	switch self.fanType {
-	case FAN_NONE:
+	switch self.FanType() {
+	case core.FAN_NONE:
 		for _, c := range self.cards {
 			c.LerpTo(self.pos)
 		}
-	case FAN_DOWN3, FAN_LEFT3, FAN_RIGHT3:
+	case core.FAN_DOWN3, core.FAN_LEFT3, core.FAN_RIGHT3:
 		for _, c := range self.cards {
 			c.LerpTo(self.pos)
 		}
 		doFan3 = true
-	case FAN_DOWN, FAN_LEFT, FAN_RIGHT:
+	case core.FAN_DOWN, core.FAN_LEFT, core.FAN_RIGHT:
 		var pos = self.pos
 		var i = 0
 		for _, c := range self.cards {
@@ -519,51 +238,16 @@ func (self *Pile) Refan() {
 	}
 }
 
-// CanMoveTail filters out cases where a tail can be moved from a given pile type
-// eg if only one card can be moved at a time
-func (self *Pile) CanMoveTail(tail []*Card) (bool, error) {
-	if !self.IsStock() {
-		if AnyCardsProne(tail) {
-			return false, errors.New("Cannot move a face down card")
-		}
-	}
-	switch self.moveType {
-	case MOVE_NONE:
-		// eg Discard, Foundation
-		return false, fmt.Errorf("Cannot move a card from a %s", self.category)
-	case MOVE_ANY:
-		// well, that was easy
-	case MOVE_ONE:
-		// eg Cell, Reserve, Stock, Waste
-		if len(tail) > 1 {
-			return false, fmt.Errorf("Can only move one card from a %s", self.category)
-		}
-	case MOVE_ONE_PLUS:
-		// don't (yet) know destination, so we allow this as MOVE_ANY
-		// and do power moves check later, in Tableau CanAcceptTail
-	case MOVE_ONE_OR_ALL:
-		// Canfield, Toad
-		if len(tail) == 1 {
-			// that's okay
-		} else if len(tail) == self.Len() {
-			// that's okay too
-		} else {
-			return false, errors.New("Can only move one card, or the whole pile")
-		}
-	}
-	return true, nil
-}
-
+// MakeTail returns a copy of the cards from c to the top of this pile
 func (self *Pile) MakeTail(c *Card) []*Card {
 	if c.Owner() != self {
 		log.Panic("Pile.MakeTail called with a card that is not of this pile")
 	}
-	if c == self.Peek() {
-		return []*Card{c}
-	}
 	for i, pc := range self.cards {
 		if pc == c {
-			return self.cards[i:]
+			var tail []*Card = make([]*Card, len(self.cards)-i)
+			copy(tail, self.cards[i:])
+			return tail
 		}
 	}
 	log.Panic("Pile.MakeTail made an empty tail")
@@ -579,26 +263,6 @@ func (self *Pile) ApplyToCards(fn func(*Card)) {
 	}
 }
 
-// default behaviours for all pile types, that can be over-ridden by providing (eg) *Stock.Collect
-
-// func (self *Pile) DefaultCanAcceptTail([]*Card) (bool, error) { return false, nil }
-
-func (self *Pile) DefaultTailTapped(tail []*Card) {
-	card := tail[0]
-	if card.tapDestination != nil {
-		src := card.Owner()
-		if len(tail) == 1 {
-			MoveCard(src, card.tapDestination)
-		} else {
-			MoveTail(card, card.tapDestination)
-		}
-	}
-	// don't play an error sound here, leave it up to higher level (Baize.InputTap)
-}
-
-// func (self *Pile) DefaultConformant() bool   { return false }
-// func (self *Pile) DefaultUnsortedPairs() int { return 0 }
-
 func (self *Pile) DrawStaticCards(screen *ebiten.Image) {
 	for _, c := range self.cards {
 		if c.Static() {
@@ -637,12 +301,8 @@ func (self *Pile) Draw(screen *ebiten.Image) {
 
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(float64(self.pos.X+TheGame.Baize.dragOffset.X), float64(self.pos.Y+TheGame.Baize.dragOffset.Y))
-	// if self.target && len(self.cards) == 0 {
-	// 	op.ColorM.Scale(0.75, 0.75, 0.75, 1)
-	// 	// op.GeoM.Translate(2, 2)
-	// }
 
-	if self.IsStock() && TheGame.Baize.Recycles() > 0 {
+	if self.IsStock() && TheGame.Baize.core.Recycles() > 0 {
 		if pt := image.Pt(ebiten.CursorPosition()); pt.In(self.ScreenRect()) {
 			if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
 				op.GeoM.Translate(2, 2)
@@ -650,20 +310,52 @@ func (self *Pile) Draw(screen *ebiten.Image) {
 		}
 	}
 
-	// if DebugMode {
-	// 	if sz := self.SizeWithFanFactor(self.fanFactor); sz != 0 {
-	// 		switch self.fanType {
-	// 		case FAN_DOWN:
-	// 			rect := self.FannedScreenRect()
-	// 			ebitenutil.DrawRect(screen,
-	// 				float64(rect.Min.X),
-	// 				float64(rect.Min.Y),
-	// 				float64(rect.Max.X-rect.Min.X),
-	// 				float64(sz),
-	// 				color.RGBA{0, 0, 0, 32})
-	// 		}
-	// 	}
-	// }
-
 	screen.DrawImage(self.img, op)
 }
+
+// Placeholder creates the image drawn where the pile is, underneath any cards
+func (self *Pile) Placeholder() *ebiten.Image {
+	if _, ok := self.core.Vtable().(*core.Waste); ok {
+		return nil
+	}
+	if _, ok := self.core.Vtable().(*core.Reserve); ok {
+		return nil
+	}
+
+	dc := gg.NewContext(CardWidth, CardHeight)
+	if clr, ok := ExtendedColors[TheGame.Settings.PlaceholderColor]; ok {
+		// the theme wants placeholders that stand out
+		dc.SetColor(clr)
+		dc.SetLineWidth(3)
+	} else {
+		dc.SetColor(color.NRGBA{255, 255, 255, 31})
+		dc.SetLineWidth(2)
+	}
+	// draw the RoundedRect entirely INSIDE the context
+	dc.DrawRoundedRectangle(1, 1, float64(CardWidth-2), float64(CardHeight-2), CardCornerRadius)
+
+	switch self.core.Vtable().(type) {
+	case *core.Stock:
+		// farted around trying to use icons for this
+		// but they were 48x48 and got fuzzy when scaled
+		// and were stubbornly white
+		var label rune
+		if TheGame.Baize.core.Recycles() == 0 {
+			label = NORECYCLE_RUNE
+		} else {
+			label = RECYCLE_RUNE
+		}
+		dc.SetFontFace(schriftbank.CardSymbolHuge)
+		dc.DrawStringAnchored(string(label), float64(CardWidth)*0.5, float64(CardHeight)*0.4, 0.5, 0.5)
+	case *core.Foundation, *core.Tableau:
+		if self.core.Label() != "" {
+			dc.SetFontFace(schriftbank.CardOrdinalLarge)
+			dc.DrawStringAnchored(self.core.Label(), float64(CardWidth)*0.5, float64(CardHeight)*0.4, 0.5, 0.5)
+		}
+	case *core.Discard:
+		dc.Fill() // difference for this subpile
+	}
+
+	dc.Stroke()
+	return ebiten.NewImageFromImage(dc.Image())
+}
@@ -0,0 +1,193 @@
+package sol
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/core"
+	"oddstream.games/gosol/sound"
+)
+
+// replaySpeeds are how many times faster than the player a replay can be watched
+var replaySpeeds = []float64{0.5, 1, 2, 4, 8}
+
+const (
+	replayMinDelay = 300 * time.Millisecond // at normal speed, so the cards are seen to move
+	replayMaxDelay = 3 * time.Second        // at normal speed, so long thinks are not sat through
+)
+
+// thisGame is listed in the replay picker, along with the replay files
+const thisGame = "This game"
+
+// replaying is what the Baize keeps while a replay is being watched instead of the game
+type replaying struct {
+	replayer *core.Replayer
+	game     *core.Baize // the game being played, put to one side
+	playing  bool
+	speed    int       // index into replaySpeeds
+	next     time.Time // when to show the next move, if playing
+}
+
+// ReplayCommandTable is used instead of CommandTable while a replay is being watched;
+// any other key stops the replay, then does what it usually does
+var ReplayCommandTable = map[ebiten.Key]func(){
+	ebiten.KeySpace:  func() { TheGame.Baize.ReplayPlayPause() },
+	ebiten.KeyP:      func() { TheGame.Baize.ReplayPlayPause() },
+	ebiten.KeyRight:  func() { TheGame.Baize.ReplayStep() },
+	ebiten.KeyY:      func() { TheGame.Baize.ReplayStep() },
+	ebiten.KeyLeft:   func() { TheGame.Baize.ReplayBack() },
+	ebiten.KeyU:      func() { TheGame.Baize.ReplayBack() },
+	ebiten.KeyUp:     func() { TheGame.Baize.ReplaySpeed(1) },
+	ebiten.KeyDown:   func() { TheGame.Baize.ReplaySpeed(-1) },
+	ebiten.KeyTab:    func() { TheGame.Baize.ReplaySpeed(0) },
+	ebiten.KeyEscape: func() { TheGame.Baize.StopReplay() },
+	ebiten.KeyMenu:   func() { TheGame.UI.ToggleNavDrawer() },
+}
+
+// ShowReplayPicker lists the game being played, and the replays that have been saved, to be watched
+func ShowReplayPicker() {
+	TheGame.UI.ShowVariantPickerEx(append([]string{thisGame}, ReplayNames()...), "WatchReplay")
+}
+
+// Replaying returns true if a replay is being watched instead of the game
+func (b *Baize) Replaying() bool {
+	return b.replay != nil
+}
+
+// game returns the game being played, even while a replay is being watched
+func (b *Baize) game() *core.Baize {
+	if b.replay != nil {
+		return b.replay.game
+	}
+	return b.core
+}
+
+// WatchReplay puts the game to one side and shows the deal of a replay, ready to be played back
+func (b *Baize) WatchReplay(r *core.Replay) {
+	var options core.Options = TheGame.Settings.Options
+	rp, err := core.NewReplayer(r, &options)
+	if err != nil {
+		TheGame.UI.ToastError(err.Error())
+		return
+	}
+	var game *core.Baize = b.game()
+	b.StopSpinning()
+	b.replay = &replaying{replayer: rp, game: game, speed: 1}
+	b.setCore(rp.Baize())
+	TheGame.UI.SetTitle("Replay of " + r.Variant)
+	TheGame.UI.ToastInfo("Space to play or pause, arrows to step and change speed, Escape to stop")
+	b.ReplayPlayPause()
+}
+
+// StopReplay goes back to the game
+func (b *Baize) StopReplay() {
+	if b.replay == nil {
+		return
+	}
+	var game *core.Baize = b.replay.game
+	b.replay = nil
+	b.setCore(game)
+	TheGame.UI.SetTitle(game.Variant())
+	TheGame.UI.HideFAB()
+	if game.Complete() {
+		b.syncCards() // so the cards spinning are the cards on the baize
+		b.StartSpinning()
+	}
+}
+
+// setCore shows another core.Baize, which may have different piles
+func (b *Baize) setCore(cb *core.Baize) {
+	b.core = cb
+	b.buildPiles()
+	sound.Play("Fan")
+	b.dirtyFlags = 0xFFFF
+	b.refresh()
+}
+
+// ReplayPlayPause starts or stops the moves of the replay being shown one after another
+func (b *Baize) ReplayPlayPause() {
+	if b.replay == nil {
+		return
+	}
+	b.replay.playing = !b.replay.playing
+	if b.replay.playing && b.replay.replayer.Index() == b.replay.replayer.Len() {
+		// start again from the deal
+		b.replay.replayer.Goto(0)
+		b.refresh()
+	}
+	b.replay.next = time.Now().Add(b.replayDelay())
+	b.showReplayControls()
+}
+
+// ReplayStep shows the next move of the replay
+func (b *Baize) ReplayStep() {
+	if !b.replay.replayer.Step() {
+		TheGame.UI.ToastInfo("End of replay")
+		return
+	}
+	sound.Play("Slide")
+	b.refresh()
+}
+
+// ReplayBack takes back the last move of the replay shown
+func (b *Baize) ReplayBack() {
+	b.replay.playing = false
+	if !b.replay.replayer.Back() {
+		TheGame.UI.ToastInfo("Start of replay")
+	}
+	b.refresh()
+}
+
+// ReplaySpeed changes how fast the replay is played; by one step faster or slower,
+// or, if step is 0, to the next speed, going round to the slowest after the fastest
+func (b *Baize) ReplaySpeed(step int) {
+	var speed int = b.replay.speed + step
+	if step == 0 {
+		speed = (speed + 1) % len(replaySpeeds)
+	}
+	if speed < 0 || speed >= len(replaySpeeds) {
+		return
+	}
+	b.replay.speed = speed
+	TheGame.UI.ToastInfo(fmt.Sprintf("Replay speed %gx", replaySpeeds[speed]))
+}
+
+// replayDelay returns how long to wait before showing the next move; the time the player took,
+// within limits, at the speed chosen
+func (b *Baize) replayDelay() time.Duration {
+	var d time.Duration = b.replay.replayer.Delay()
+	if d < replayMinDelay {
+		d = replayMinDelay
+	} else if d > replayMaxDelay {
+		d = replayMaxDelay
+	}
+	return time.Duration(float64(d) / replaySpeeds[b.replay.speed])
+}
+
+// updateReplay shows the next move of a replay that is playing, when it is time to
+func (b *Baize) updateReplay() {
+	if b.replay == nil || !b.replay.playing || time.Now().Before(b.replay.next) {
+		return
+	}
+	b.ReplayStep()
+	if b.replay.replayer.Index() == b.replay.replayer.Len() {
+		b.replay.playing = false
+		TheGame.UI.ToastInfo("End of replay")
+		b.showReplayControls()
+		return
+	}
+	b.replay.next = time.Now().Add(b.replayDelay())
+}
+
+// showReplayControls puts the replay buttons in the FAB; the toolbar undo and redo buttons step through the moves
+func (b *Baize) showReplayControls() {
+	TheGame.UI.HideFAB()
+	TheGame.UI.AddButtonToFAB("close", ebiten.KeyEscape)
+	TheGame.UI.AddButtonToFAB("speed", ebiten.KeyTab)
+	if b.replay.playing {
+		TheGame.UI.AddButtonToFAB("pause", ebiten.KeySpace)
+	} else {
+		TheGame.UI.AddButtonToFAB("play_arrow", ebiten.KeySpace)
+	}
+}
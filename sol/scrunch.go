@@ -4,6 +4,8 @@ package sol
 
 import (
 	"fmt"
+
+	"oddstream.games/gosol/core"
 )
 
 // func (b *Baize) FindBuddyPiles() {
@@ -15,15 +17,15 @@ import (
 // 				switch (p2).(type) {
 // 				case *Tableau, *Reserve:
 // 					switch p1.fanType {
-// 					case FAN_DOWN:
+// 					case core.FAN_DOWN:
 // 						if p1.slot.X == p2.slot.X && p2.slot.Y > p1.slot.Y {
 // 							p1.buddyPos = p2.pos
 // 						}
-// 					case FAN_LEFT:
+// 					case core.FAN_LEFT:
 // 						if p1.slot.Y == p2.slot.Y && p2.slot.X < p1.slot.X {
 // 							p1.buddyPos = p2.pos
 // 						}
-// 					case FAN_RIGHT:
+// 					case core.FAN_RIGHT:
 // 						if p1.slot.Y == p2.slot.Y && p2.slot.X > p1.slot.X {
 // 							p1.buddyPos = p2.pos
 // 						}
@@ -39,20 +41,20 @@ import (
 // 		switch (pile).(type) {
 // 		case *Tableau, *Reserve:
 // 			switch pile.FanType() {
-// 			case FAN_DOWN:
+// 			case core.FAN_DOWN:
 // 				if pile.buddyPos.Y != 0 {
 // 					pile.scrunchDims.Y = pile.buddyPos.Y - pile.pos.Y
 // 				} else {
 // 					// baize->dragOffset is always -ve
 // 					pile.scrunchDims.Y = h - pile.pos.Y + util.Abs(b.dragOffset.Y)
 // 				}
-// 			case FAN_LEFT:
+// 			case core.FAN_LEFT:
 // 				if pile.buddyPos.X != 0 {
 // 					pile.scrunchDims.X = pile.buddyPos.X - pile.pos.X
 // 				} else {
 // 					pile.scrunchDims.X = pile.pos.X
 // 				}
-// 			case FAN_RIGHT:
+// 			case core.FAN_RIGHT:
 // 				if pile.buddyPos.X != 0 {
 // 					pile.scrunchDims.X = pile.buddyPos.X - pile.pos.X
 // 				} else {
@@ -68,8 +70,8 @@ import (
 // SizeWithFanFactor calculates the width or height this pile would be if it had a specified fan factor
 func (self *Pile) SizeWithFanFactor(fanFactor float64) int {
 	var max int
-	switch self.fanType {
-	case FAN_DOWN:
+	switch self.FanType() {
+	case core.FAN_DOWN:
 		for i := 0; i < len(self.cards)-1; i++ {
 			c := self.cards[i]
 			if c.Prone() {
@@ -79,7 +81,7 @@ func (self *Pile) SizeWithFanFactor(fanFactor float64) int {
 			}
 		}
 		max += CardHeight
-	case FAN_LEFT, FAN_RIGHT:
+	case core.FAN_LEFT, core.FAN_RIGHT:
 		for i := 0; i < len(self.cards)-1; i++ {
 			c := self.cards[i]
 			if c.Prone() {
@@ -97,7 +99,7 @@ func (self *Pile) SizeWithFanFactor(fanFactor float64) int {
 // only Scrunch piles with fanType LEFT/RIGHT/UP/DOWN, ignore the waste-style piles and those that do not fan
 func (self *Pile) Scrunch() {
 
-	self.fanFactor = DefaultFanFactor[self.fanType]
+	self.fanFactor = DefaultFanFactor[self.FanType()]
 
 	if NoScrunch || len(self.cards) < 2 {
 		self.Refan()
@@ -105,15 +107,15 @@ func (self *Pile) Scrunch() {
 	}
 
 	var maxPileSize int
-	switch self.fanType {
-	case FAN_DOWN:
+	switch self.FanType() {
+	case core.FAN_DOWN:
 		// baize->dragOffset is always -ve
 		// statusbar height is 24
 		// maxPileSize = TheGame.Baize.WindowHeight - scpos.Y + util.Abs(TheGame.Baize.dragOffset.Y)
 		maxPileSize = TheGame.Baize.WindowHeight - self.ScreenPos().Y + (CardHeight / 2)
-	case FAN_LEFT:
+	case core.FAN_LEFT:
 		maxPileSize = self.ScreenPos().X
-	case FAN_RIGHT:
+	case core.FAN_RIGHT:
 		// baize->dragOffset is always -ve
 		// maxPileSize = TheGame.Baize.WindowWidth - scpos.X + util.Abs(TheGame.Baize.dragOffset.X)
 		maxPileSize = TheGame.Baize.WindowWidth - self.ScreenPos().X
@@ -126,14 +128,14 @@ func (self *Pile) Scrunch() {
 
 	var nloops int
 	var fanFactor float64
-	for fanFactor = DefaultFanFactor[self.fanType]; fanFactor < 7.0; fanFactor += 0.1 {
+	for fanFactor = DefaultFanFactor[self.FanType()]; fanFactor < 7.0; fanFactor += 0.1 {
 		size := self.SizeWithFanFactor(fanFactor)
-		switch self.fanType {
-		case FAN_DOWN:
+		switch self.FanType() {
+		case core.FAN_DOWN:
 			if size < maxPileSize {
 				goto exitloop
 			}
-		case FAN_LEFT, FAN_RIGHT:
+		case core.FAN_LEFT, core.FAN_RIGHT:
 			if size < maxPileSize {
 				goto exitloop
 			}
@@ -145,7 +147,7 @@ func (self *Pile) Scrunch() {
 exitloop:
 	self.fanFactor = fanFactor
 	if DebugMode && nloops > 0 {
-		fmt.Printf("%d loops to go from %f to %f", nloops, DefaultFanFactor[self.fanType], self.fanFactor)
+		fmt.Printf("%d loops to go from %f to %f", nloops, DefaultFanFactor[self.FanType()], self.fanFactor)
 		fmt.Printf(" WindowWidth, Height = %d,%d\n", TheGame.Baize.WindowWidth, TheGame.Baize.WindowHeight)
 	}
 	self.Refan()
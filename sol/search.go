@@ -0,0 +1,40 @@
+package sol
+
+// searches for a win can take seconds, so they run in a goroutine, on a copy of the game,
+// while the game keeps being drawn; what to do with the result is handed back to Update
+
+// search is the one search that may be running
+type search struct {
+	running bool
+	done    chan func() // what to do with the result, run by Update
+}
+
+// startSearch runs work in a goroutine; the func that work returns is run by Update when it is done.
+// Only one search runs at a time, so it returns false if one is already running.
+func (b *Baize) startSearch(work func() func()) bool {
+	if b.search.running {
+		TheGame.UI.ToastInfo("Still thinking about the last thing you asked")
+		return false
+	}
+	if b.search.done == nil {
+		b.search.done = make(chan func(), 1) // buffered, so a search outliving this Baize does not leak
+	}
+	b.search.running = true
+	go func(done chan func()) {
+		done <- work()
+	}(b.search.done)
+	return true
+}
+
+// finishSearch is called by Update, and uses the result of the search if it has finished
+func (b *Baize) finishSearch() {
+	if !b.search.running {
+		return
+	}
+	select {
+	case fn := <-b.search.done:
+		b.search.running = false
+		fn()
+	default:
+	}
+}
@@ -1,6 +1,7 @@
 package sol
 
 import (
+	"oddstream.games/gosol/core"
 	"oddstream.games/gosol/sound"
 	"oddstream.games/gosol/ui"
 )
@@ -10,6 +11,7 @@ import (
 type Settings struct {
 	// Capitals to emit to json
 	Variant                            string
+	Theme                              string // the name of the theme the colors below were last set from
 	BaizeColor                         string
 	CardFaceColor                      string
 	CardBackColor                      string
@@ -21,15 +23,19 @@ type Settings struct {
 	HeartColor                         string
 	SpadeColor                         string
 	ColorfulCards                      bool
-	PowerMoves                         bool
-	SafeCollect, AutoCollect           bool
+	PlaceholderColor                   string // "" for a faint outline
+	LargeIndices                       bool   // draw card ordinals and suits large
+	CardPack                           string // the directory in cards/ in the config directory to load card images from, "" for none
+	core.Options                              // PowerMoves, SafeCollect, AutoCollect, MirrorBaize
 	Mute                               bool
 	Volume                             float64
-	MirrorBaize                        bool
 	ShowMovableCards                   bool
 	AlwaysShowMovableCards             bool
+	MicrosoftDeals                     bool
+	WinnableDeals                      map[string]bool // variants that only deal games the solver can win
 	CardRatio                          float64
 	AniSpeed                           float64
+	ScreenReader                       bool // describe the game in words, for players who cannot see it
 	LastVersionMajor, LastVersionMinor int
 	// FixedCards                         bool
 	// FixedCardWidth, FixedCardHeight    int
@@ -38,10 +44,8 @@ type Settings struct {
 func NewSettings() *Settings {
 	s := &Settings{
 		Variant:                "Klondike",
+		Theme:                  "Classic",
 		BaizeColor:             "BaizeGreen",
-		PowerMoves:             true,
-		SafeCollect:            false,
-		AutoCollect:            false,
 		CardFaceColor:          "Ivory",
 		CardBackColor:          "CornflowerBlue",
 		MovableCardBackColor:   "Gold",
@@ -56,6 +60,12 @@ func NewSettings() *Settings {
 		Volume:                 0.75,
 		ShowMovableCards:       false,
 		AlwaysShowMovableCards: false,
+		WinnableDeals:          map[string]bool{},
+		Options: core.Options{
+			PowerMoves:  true,
+			SafeCollect: false,
+			AutoCollect: false,
+		},
 		// FixedCards:       false,
 		// FixedCardWidth:   90,
 		// FixedCardHeight:  122,
@@ -69,12 +79,15 @@ func NewSettings() *Settings {
 }
 
 func ShowSettingsDrawer() {
+	var variant string = TheGame.Baize.core.Variant()
+	var winnable bool = TheGame.Settings.WinnableDeals[variant]
 	var BooleanSettings = []ui.BooleanSetting{
 		{Title: "Power moves", Var: &TheGame.Settings.PowerMoves},
 		{Title: "Auto collect", Var: &TheGame.Settings.AutoCollect},
 		{Title: "Safe collect", Var: &TheGame.Settings.SafeCollect},
 		{Title: "Show movable cards", Var: &TheGame.Settings.ShowMovableCards},
 		{Title: "Colorful cards", Var: &TheGame.Settings.ColorfulCards, Update: func() { TheGame.Baize.setFlag(dirtyCardImages) }},
+		{Title: "Large card indices", Var: &TheGame.Settings.LargeIndices, Update: func() { TheGame.Baize.setFlag(dirtyCardImages) }},
 		{Title: "Mute sounds", Var: &TheGame.Settings.Mute, Update: func() {
 			if TheGame.Settings.Mute {
 				sound.SetVolume(0.0)
@@ -82,16 +95,35 @@ func ShowSettingsDrawer() {
 				sound.SetVolume(TheGame.Settings.Volume)
 			}
 		}},
+		{Title: "Screen reader", Var: &TheGame.Settings.ScreenReader},
 		{Title: "Mirror baize", Var: &TheGame.Settings.MirrorBaize, Update: func() {
-			savedUndoStack := TheGame.Baize.undoStack
+			saved, err := TheGame.Baize.core.MarshalGame()
 			TheGame.Baize.StartFreshGame()
-			TheGame.Baize.SetUndoStack(savedUndoStack)
+			if err == nil {
+				TheGame.Baize.SetGame(saved)
+			}
+		}},
+		{Title: "Winnable deals only", Var: &winnable, Update: func() {
+			if TheGame.Settings.WinnableDeals == nil {
+				TheGame.Settings.WinnableDeals = map[string]bool{}
+			}
+			TheGame.Settings.WinnableDeals[variant] = winnable
 		}},
 	}
 
 	TheGame.UI.ShowSettingsDrawer(&BooleanSettings)
 }
 
+// ShowDealDrawer lets the player type a deal number, and choose Microsoft
+// FreeCell numbering if the current variant has it
+func ShowDealDrawer() {
+	var msDeals *bool
+	if TheGame.Baize.core.Script().MicrosoftDeals() {
+		msDeals = &TheGame.Settings.MicrosoftDeals
+	}
+	TheGame.UI.ShowDealDrawer(TheGame.Baize.DealNumber(), msDeals)
+}
+
 func ShowAniSpeedDrawer() {
 	var AniSpeedSettings = []ui.FloatSetting{
 		{Title: "Fast", Var: &TheGame.Settings.AniSpeed, Value: 0.3},
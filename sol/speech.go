@@ -0,0 +1,132 @@
+package sol
+
+import (
+	"fmt"
+	"strings"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// Players who cannot see the baize are told what is happening in words: where the keyboard cursor is,
+// what each move did, and, as every toast is announced, why a move was not allowed.
+// On the web the words go to an ARIA live region for the browser's screen reader; on the desktop they are
+// spoken, if the system can speak, and written to speech.txt in the config directory.
+
+var spokenOrdinals = []string{"", "Ace", "Two", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine", "Ten", "Jack", "Queen", "King"}
+
+// Announce tells the player something in words, if they have asked for that in the settings
+func Announce(msg string) {
+	if TheGame.Settings.ScreenReader && msg != "" {
+		announce(msg)
+	}
+}
+
+// spokenCard names a card the way a player would say it, eg "Seven of Hearts"
+func spokenCard(cid cardid.CardID) string {
+	if cid.Prone() {
+		return "a face down card"
+	}
+	if cid.Joker() {
+		return "Joker"
+	}
+	if cid.Ordinal() < 1 || cid.Ordinal() >= len(spokenOrdinals) {
+		return cid.String()
+	}
+	return spokenOrdinals[cid.Ordinal()] + " of " + cid.StringSuit() + "s"
+}
+
+// spokenPile names a pile by its category, numbered from 1 if the baize has more than one, eg "Tableau 3"
+func (b *Baize) spokenPile(p *Pile) string {
+	var n, count int
+	for _, q := range b.piles {
+		if q.core.Category() == p.core.Category() {
+			count++
+			if q == p {
+				n = count
+			}
+		}
+	}
+	if count == 1 {
+		return p.core.Category()
+	}
+	return fmt.Sprintf("%s %d", p.core.Category(), n)
+}
+
+// announceCursor describes the pile and card under the keyboard cursor
+func (b *Baize) announceCursor() {
+	var p *Pile = b.cursorPile()
+	if p == nil {
+		return
+	}
+	var words []string = []string{b.spokenPile(p)}
+	if label := p.core.Label(); label != "" {
+		words = append(words, "for "+label)
+	}
+	if c := b.cursorCard(); c == nil {
+		words = append(words, "empty")
+	} else if b.cursor.card == p.Len()-1 {
+		words = append(words, spokenCard(c.id), fmt.Sprintf("%d cards", p.Len()))
+	} else {
+		words = append(words, spokenCard(c.id), fmt.Sprintf("card %d of %d", b.cursor.card+1, p.Len()))
+	}
+	Announce(strings.Join(words, ", "))
+}
+
+// announceTail describes a tail of cards, eg "Seven of Hearts and 2 more cards"
+func announceTail(tail []*Card) string {
+	switch len(tail) {
+	case 0:
+		return "no cards"
+	case 1:
+		return spokenCard(tail[0].id)
+	case 2:
+		return spokenCard(tail[0].id) + " and 1 more card"
+	default:
+		return fmt.Sprintf("%s and %d more cards", spokenCard(tail[0].id), len(tail)-1)
+	}
+}
+
+// announcePickUp says what the keyboard cursor has picked up, and where it can go
+func (b *Baize) announcePickUp() {
+	var places []string
+	for _, h := range b.cursor.homes {
+		for _, p := range b.piles {
+			if p.core == h {
+				places = append(places, b.spokenPile(p))
+			}
+		}
+	}
+	if len(places) == 0 {
+		Announce(fmt.Sprintf("Picked up %s, which cannot go anywhere", announceTail(b.cursor.tail)))
+	} else {
+		Announce(fmt.Sprintf("Picked up %s, which can go to %s", announceTail(b.cursor.tail), strings.Join(places, ", ")))
+	}
+}
+
+// announceMove says that a tail of cards, named before it moved, has been put on dst
+func (b *Baize) announceMove(tail string, dst *Pile) {
+	Announce(fmt.Sprintf("Moved %s to %s", tail, b.spokenPile(dst)))
+}
+
+// announceDeal says that a stock (or other pile) has been tapped, and how many cards are left in it
+func (b *Baize) announceDeal(p *Pile) {
+	Announce(fmt.Sprintf("Tapped %s, %d cards left in it", b.spokenPile(p), p.Len()))
+}
+
+// foundationCards counts the cards on the foundations, to tell how many were collected
+func (b *Baize) foundationCards() int {
+	var n int
+	for _, f := range b.core.Script().Foundations() {
+		n += f.Len()
+	}
+	return n
+}
+
+// announceCollect says how many cards were collected to the foundations
+func (b *Baize) announceCollect(n int) {
+	if n == 1 {
+		Announce("Collected 1 card to the foundations")
+	} else if n > 1 {
+		Announce(fmt.Sprintf("Collected %d cards to the foundations", n))
+	}
+}
@@ -0,0 +1,65 @@
+//go:build linux || windows || android || darwin
+
+package sol
+
+import (
+	"errors"
+	"log"
+	"os/exec"
+	"sync/atomic"
+	"time"
+
+	"oddstream.games/gosol/util"
+)
+
+var (
+	speech       chan string // announcements waiting to be spoken, one after another
+	speechLogged bool        // speech.txt has been started afresh this session
+	speechLost   atomic.Bool // set by speak if the system has nothing to speak with
+	speechTold   bool        // the player has been told that speech is lost
+)
+
+func announce(msg string) {
+	var line []byte = []byte(time.Now().Format("15:04:05") + " " + msg + "\n")
+	if speechLogged {
+		util.AppendBytesToFile(line, "speech.txt")
+	} else {
+		util.SaveBytesToFile(line, "speech.txt")
+		speechLogged = true
+	}
+
+	if speechLost.Load() {
+		if !speechTold {
+			speechTold = true // before the toast, which is announced too
+			TheGame.UI.ToastError("This computer cannot speak, so announcements are only written to speech.txt")
+		}
+		return
+	}
+	if speech == nil {
+		speech = make(chan string, 8)
+		go speak()
+	}
+	select {
+	case speech <- msg:
+	default:
+		// the player is pressing keys faster than they can be spoken to
+	}
+}
+
+// speak says each announcement in turn, giving up if the system has no command to speak with;
+// any other failure, eg a busy speech daemon, only loses that announcement
+func speak() {
+	for msg := range speech {
+		err := speechCommand(msg).Run()
+		if err == nil {
+			continue
+		}
+		log.Println(err)
+		if errors.Is(err, exec.ErrNotFound) {
+			speechLost.Store(true)
+			for range speech {
+				// drain, so announce never blocks
+			}
+		}
+	}
+}
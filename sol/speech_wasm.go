@@ -0,0 +1,22 @@
+package sol
+
+import (
+	"syscall/js"
+)
+
+// liveRegion is an offscreen element the browser's screen reader reads out whenever its text changes
+var liveRegion js.Value
+
+func announce(msg string) {
+	if liveRegion.IsUndefined() {
+		var document js.Value = js.Global().Get("document")
+		liveRegion = document.Call("createElement", "div")
+		liveRegion.Call("setAttribute", "role", "status")
+		liveRegion.Call("setAttribute", "aria-live", "polite")
+		liveRegion.Get("style").Set("cssText", "position:absolute;left:-10000px;width:1px;height:1px;overflow:hidden")
+		document.Get("body").Call("appendChild", liveRegion)
+	}
+	// clear it first, so saying the same thing twice is read out twice
+	liveRegion.Set("textContent", "")
+	liveRegion.Set("textContent", msg)
+}
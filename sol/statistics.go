@@ -20,6 +20,10 @@ type VariantStatistics struct {
 	// Won + Lost is total number of games played (won or abandoned)
 	// SumPercents is a record of games where % < 100
 	// average % is (sum of Percents) + (100 * Won) / (Won+Lost)
+	BestMovesDeal, BestPercentDeal, LastDeal uint64 `json:",omitempty"`
+	// the deal numbers of the best win, the best incomplete game, and the last game played
+	WinnableWon, WinnableLost int `json:",omitempty"`
+	// the games (included in Won and Lost) that were dealt by the winnable deals only setting
 }
 
 // NewStatistics creates a new Statistics object (a map)
@@ -50,6 +54,10 @@ func (stats *VariantStatistics) strings(v string) []string {
 		strs = append(strs, fmt.Sprintf("Lost: %d", stats.Lost))
 		winRate := (stats.Won * 100) / (stats.Won + stats.Lost)
 		strs = append(strs, fmt.Sprintf("Win rate: %d%%", winRate))
+		if stats.WinnableWon+stats.WinnableLost > 0 {
+			strs = append(strs, fmt.Sprintf("Winnable deals played: %d", stats.WinnableWon+stats.WinnableLost))
+			strs = append(strs, fmt.Sprintf("Winnable deals won: %d", stats.WinnableWon))
+		}
 		strs = append(strs, " ")
 
 		avpc := stats.averagePercent()
@@ -59,10 +67,10 @@ func (stats *VariantStatistics) strings(v string) []string {
 		if stats.BestPercent < 100 {
 			// not yet won a game
 			strs = append(strs, "You have yet to win a game")
-			strs = append(strs, fmt.Sprintf("Best percent: %d%%", stats.BestPercent))
+			strs = append(strs, fmt.Sprintf("Best percent: %d%%%s", stats.BestPercent, dealString(stats.BestPercentDeal)))
 		} else {
 			// won at least one game
-			strs = append(strs, fmt.Sprintf("Best number of moves: %d", stats.BestMoves))
+			strs = append(strs, fmt.Sprintf("Best number of moves: %d%s", stats.BestMoves, dealString(stats.BestMovesDeal)))
 			strs = append(strs, fmt.Sprintf("Worst number of moves: %d", stats.WorstMoves))
 			strs = append(strs, fmt.Sprintf("Average number of moves: %d", stats.SumMoves/stats.Won))
 		}
@@ -81,6 +89,14 @@ func (stats *VariantStatistics) strings(v string) []string {
 	return strs
 }
 
+// dealString is a helper function, formatting a deal number if it is known
+func dealString(seed uint64) string {
+	if seed == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (deal %d)", seed)
+}
+
 func (s *Statistics) strings() []string {
 	var strs []string = []string{}
 	var numPlayed, numWon, numLost int
@@ -112,11 +128,15 @@ func (s *Statistics) Played(v string) int {
 	return vstats.Won + vstats.Lost
 }
 
-func (s *Statistics) RecordWonGame(v string, moves int) string {
+func (s *Statistics) RecordWonGame(v string, seed uint64, moves int, winnable bool) string {
 
 	vstats := s.findVariant(v)
+	vstats.LastDeal = seed
 
 	vstats.Won = vstats.Won + 1
+	if winnable {
+		vstats.WinnableWon++
+	}
 
 	if vstats.CurrStreak < 0 {
 		vstats.CurrStreak = 1
@@ -131,6 +151,7 @@ func (s *Statistics) RecordWonGame(v string, moves int) string {
 
 	if vstats.BestMoves == 0 || moves < vstats.BestMoves {
 		vstats.BestMoves = moves
+		vstats.BestMovesDeal = seed
 	}
 	if vstats.WorstMoves == 0 || moves > vstats.WorstMoves {
 		vstats.WorstMoves = moves
@@ -142,11 +163,15 @@ func (s *Statistics) RecordWonGame(v string, moves int) string {
 	return fmt.Sprintf("Recording completed game of %s", v)
 }
 
-func (s *Statistics) RecordLostGame(v string, percent int) string {
+func (s *Statistics) RecordLostGame(v string, seed uint64, percent int, winnable bool) string {
 
 	vstats := s.findVariant(v)
+	vstats.LastDeal = seed
 
 	vstats.Lost = vstats.Lost + 1
+	if winnable {
+		vstats.WinnableLost++
+	}
 	// don't see that currStreak can ever be zero
 	if vstats.CurrStreak > 0 {
 		vstats.CurrStreak = -1
@@ -159,6 +184,7 @@ func (s *Statistics) RecordLostGame(v string, percent int) string {
 
 	if percent > vstats.BestPercent {
 		vstats.BestPercent = percent
+		vstats.BestPercentDeal = seed
 	}
 	vstats.SumPercents += percent
 
@@ -168,8 +194,8 @@ func (s *Statistics) RecordLostGame(v string, percent int) string {
 }
 
 func ShowStatisticsDrawer() {
-	vstats := TheGame.Statistics.findVariant(TheGame.Baize.variant)
-	var strs []string = vstats.strings(TheGame.Baize.variant)
+	vstats := TheGame.Statistics.findVariant(TheGame.Baize.core.Variant())
+	var strs []string = vstats.strings(TheGame.Baize.core.Variant())
 	strs = append(strs, " ") // n.b. can't use empty string
 	strs = append(strs, "ALL VARIANTS")
 	strs = append(strs, TheGame.Statistics.strings()...)
@@ -0,0 +1,100 @@
+package sol
+
+// Theme is a set of colors, and how the card faces and placeholders are drawn, chosen together
+// so that the cards can be told apart by players who find the usual colors hard to see
+type Theme struct {
+	Name                                            string
+	BaizeColor, CardFaceColor                       string
+	CardBackColor, MovableCardBackColor             string
+	BlackColor, RedColor                            string
+	ClubColor, DiamondColor, HeartColor, SpadeColor string
+	ColorfulCards                                   bool
+	PlaceholderColor                                string // "" for a faint outline
+	LargeIndices                                    bool   // draw the ordinal and suit large, at the top of the face
+}
+
+var Themes = []Theme{
+	{
+		Name:       "Classic",
+		BaizeColor: "BaizeGreen", CardFaceColor: "Ivory",
+		CardBackColor: "CornflowerBlue", MovableCardBackColor: "Gold",
+		BlackColor: "Black", RedColor: "Crimson",
+		ClubColor: "DarkGreen", DiamondColor: "DarkBlue", HeartColor: "Crimson", SpadeColor: "Black",
+		ColorfulCards: true,
+	},
+	{
+		// black and white, with a dark red that is still dark to those who cannot see red
+		Name:       "High contrast",
+		BaizeColor: "Black", CardFaceColor: "White",
+		CardBackColor: "DodgerBlue", MovableCardBackColor: "Yellow",
+		BlackColor: "Black", RedColor: "DarkRed",
+		ClubColor: "Black", DiamondColor: "DarkRed", HeartColor: "DarkRed", SpadeColor: "Black",
+		ColorfulCards:    false,
+		PlaceholderColor: "White",
+		LargeIndices:     true,
+	},
+	{
+		// suits in black, blue, orange and purple, which those with deuteranopia or protanopia can tell apart
+		Name:       "Color-blind four color",
+		BaizeColor: "DarkSlateGray", CardFaceColor: "White",
+		CardBackColor: "RoyalBlue", MovableCardBackColor: "Gold",
+		BlackColor: "Black", RedColor: "DarkOrange",
+		ClubColor: "MediumBlue", DiamondColor: "DarkOrange", HeartColor: "MediumVioletRed", SpadeColor: "Black",
+		ColorfulCards:    true,
+		PlaceholderColor: "Silver",
+	},
+	{
+		Name:       "Large print",
+		BaizeColor: "BaizeGreen", CardFaceColor: "Ivory",
+		CardBackColor: "CornflowerBlue", MovableCardBackColor: "Gold",
+		BlackColor: "Black", RedColor: "Crimson",
+		ClubColor: "DarkGreen", DiamondColor: "DarkBlue", HeartColor: "Crimson", SpadeColor: "Black",
+		ColorfulCards: true,
+		LargeIndices:  true,
+	},
+}
+
+// ThemeNames returns the names of the themes, for the theme picker
+func ThemeNames() []string {
+	var names []string
+	for _, t := range Themes {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// ShowThemePicker lets the player choose a theme
+func ShowThemePicker() {
+	TheGame.UI.ShowVariantPickerEx(ThemeNames(), "ChangeTheme")
+}
+
+// ApplyTheme copies the colors and styles of the named theme into the settings,
+// returning false if there is no theme of that name
+func (s *Settings) ApplyTheme(name string) bool {
+	for _, t := range Themes {
+		if t.Name != name {
+			continue
+		}
+		s.Theme = t.Name
+		s.BaizeColor, s.CardFaceColor = t.BaizeColor, t.CardFaceColor
+		s.CardBackColor, s.MovableCardBackColor = t.CardBackColor, t.MovableCardBackColor
+		s.BlackColor, s.RedColor = t.BlackColor, t.RedColor
+		s.ClubColor, s.DiamondColor, s.HeartColor, s.SpadeColor = t.ClubColor, t.DiamondColor, t.HeartColor, t.SpadeColor
+		s.ColorfulCards = t.ColorfulCards
+		s.PlaceholderColor = t.PlaceholderColor
+		s.LargeIndices = t.LargeIndices
+		return true
+	}
+	return false
+}
+
+// ChangeTheme switches to the named theme, redrawing the cards and placeholders
+func (b *Baize) ChangeTheme(name string) {
+	if !TheGame.Settings.ApplyTheme(name) {
+		TheGame.UI.ToastError("Unknown theme " + name)
+		return
+	}
+	b.setFlag(dirtyCardImages | dirtyPileBackgrounds)
+	TheGame.Settings.Save()
+	TheGame.UI.ToastInfo("Theme " + name)
+}
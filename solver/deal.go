@@ -0,0 +1,27 @@
+package solver
+
+import (
+	"time"
+
+	"oddstream.games/gosol/core"
+)
+
+// FindWinnableDeal deals games of variant until it finds one the solver can win,
+// returning its deal number and true. If it runs out of time, even part way through
+// solving a deal, it returns a deal number and false, so the caller can play that instead.
+func FindWinnableDeal(variant string, options *core.Options, timeout time.Duration) (uint64, bool) {
+	var deadline time.Time = time.Now().Add(timeout)
+	var seed uint64 = core.NewSeed()
+	for time.Now().Before(deadline) {
+		b := core.NewBaize(variant, options)
+		if b == nil {
+			return seed, false
+		}
+		b.StartFreshGame(seed)
+		if result, _, _ := solveBy(b, DefaultMaxNodes(b), deadline); result == SOLVABLE {
+			return seed, true
+		}
+		seed = core.NewSeed()
+	}
+	return seed, false
+}
@@ -0,0 +1,170 @@
+package solver
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"oddstream.games/gosol/core"
+)
+
+// generic searches any variant by playing it on a copy of the Baize, using the variant's own rules;
+// it is much slower than the Freecell solver, but knows every game.
+// Face down cards are no secret to it, so it answers "could this deal be won by someone who could see every card?"
+type generic struct {
+	b        *core.Baize // the copy being played with
+	maxNodes int
+	deadline time.Time // when to give up; zero for never
+	seen     map[string]struct{}
+	aborted  bool
+}
+
+// solveGeneric searches for a way to win from the current position on b, without changing b
+func solveGeneric(b *core.Baize, maxNodes int, deadline time.Time) (Result, []Move, error) {
+	g := &generic{b: b.Copy(), maxNodes: maxNodes, deadline: deadline, seen: make(map[string]struct{})}
+	path, ok := g.search()
+	if ok {
+		var moves []Move = make([]Move, 0, len(path))
+		for i := len(path) - 1; i >= 0; i-- {
+			// the moves are between the piles of the copy; give back the same piles of the original
+			moves = append(moves, g.original(b, path[i]))
+		}
+		return SOLVABLE, moves, nil
+	}
+	if g.aborted {
+		return UNKNOWN, nil, nil
+	}
+	return UNSOLVABLE, nil, nil
+}
+
+// search does a depth first search from the position on the copy, trying moves to the foundations first,
+// and dealing from the stock last. It returns the winning moves, last move first.
+func (g *generic) search() ([]Move, bool) {
+	if g.b.Complete() {
+		return nil, true
+	}
+	var key string = g.key()
+	if _, ok := g.seen[key]; ok {
+		return nil, false
+	}
+	if len(g.seen) >= g.maxNodes || outOfTime(g.deadline, len(g.seen)) {
+		g.aborted = true
+		return nil, false
+	}
+	g.seen[key] = struct{}{}
+
+	var sav *core.SavableBaize = g.b.Position()
+	var moved bool
+	for _, m := range g.moves() {
+		if moved {
+			// put the cards back to try another move
+			g.b.SetPosition(sav)
+		}
+		if moved = g.play(m); !moved {
+			continue
+		}
+		if path, ok := g.search(); ok {
+			return append(path, m), true
+		}
+		if g.aborted {
+			break
+		}
+	}
+	return nil, false
+}
+
+// play makes a move, as if the player had dragged or tapped the cards, returning false if nothing changed
+func (g *generic) play(m Move) bool {
+	if m.Tap() {
+		crc := g.b.CRC()
+		if m.Src.Empty() {
+			g.b.PileTapped(m.Src)
+		} else {
+			g.b.TailTapped([]*core.Card{m.Src.Peek()})
+		}
+		if crc == g.b.CRC() {
+			return false
+		}
+	} else {
+		var cards []*core.Card = m.Src.Cards()
+		if err := g.b.MoveTailTo(cards[len(cards)-m.Count:], m.Dst); err != nil {
+			return false
+		}
+	}
+	// the rest of AfterUserMove is for the benefit of the player
+	g.b.Script().AfterMove()
+	return true
+}
+
+// moves returns every move that can be made, best looking first
+func (g *generic) moves() []Move {
+	type weighted struct {
+		Move
+		weight int
+	}
+	var moves []weighted
+	for _, p := range g.b.Piles() {
+		for _, mt := range p.Vtable().MovableTails() {
+			var dst *core.Pile = mt.Dst()
+			if dst.Empty() && len(mt.Tail()) == p.Len() && dst.Category() == p.Category() && dst.Label() == p.Label() {
+				// moving a whole pile to an empty pile just like it
+				continue
+			}
+			var weight int
+			switch dst.Vtable().(type) {
+			case *core.Foundation, *core.Discard:
+				weight = 4
+			case *core.Tableau:
+				if !dst.Empty() && dst.Peek().Suit() == mt.Tail()[0].Suit() {
+					weight = 3
+				} else {
+					weight = 2
+				}
+			default:
+				weight = 1
+			}
+			moves = append(moves, weighted{Move: Move{Src: p, Dst: dst, Count: len(mt.Tail())}, weight: weight})
+		}
+	}
+	var stock *core.Pile = g.b.Script().Stock()
+	if !stock.Hidden() && (!stock.Empty() || g.b.Recycles() > 0) {
+		moves = append(moves, weighted{Move: Move{Src: stock}, weight: 0})
+	}
+	sort.SliceStable(moves, func(i, j int) bool { return moves[i].weight > moves[j].weight })
+	var result []Move = make([]Move, 0, len(moves))
+	for _, m := range moves {
+		result = append(result, m.Move)
+	}
+	return result
+}
+
+// key makes a string describing the position on the copy, ignoring which pack a card comes from
+// and which cell it is in, so positions that play the same are only searched once
+func (g *generic) key() string {
+	var sb strings.Builder
+	var cells []string
+	for _, p := range g.b.Piles() {
+		var pile []byte = make([]byte, 0, p.Len()+1)
+		for _, c := range p.Cards() {
+			var b byte = byte(c.Suit()<<4 | c.Ordinal())
+			if c.Prone() {
+				b |= 0x80
+			}
+			pile = append(pile, b)
+		}
+		if _, ok := p.Vtable().(*core.Cell); ok {
+			cells = append(cells, string(pile))
+			continue
+		}
+		sb.Write(pile)
+		sb.WriteByte(0xff)
+	}
+	sort.Strings(cells)
+	for _, c := range cells {
+		sb.WriteString(c)
+		sb.WriteByte(0xff)
+	}
+	sb.WriteString(strconv.Itoa(g.b.Recycles()))
+	return sb.String()
+}
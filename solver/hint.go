@@ -0,0 +1,120 @@
+package solver
+
+import (
+	"sort"
+	"time"
+
+	"oddstream.games/gosol/core"
+)
+
+// hintDepth is the number of moves Hints looks ahead
+const hintDepth = 2
+
+// hintMaxNodes is the number of positions the Freecell solver may look at to find a hint
+const hintMaxNodes = 20000
+
+// Hints returns the moves that can be made from the current position on b, best first,
+// without changing b. Moves are ranked by how far on the game could be a couple of moves later;
+// moves back to a position already seen in this game come last.
+// If the Freecell solver can quickly find a win, the first move of that comes first.
+func Hints(b *core.Baize) []Move {
+	g := &generic{b: b.Copy()}
+	var start *core.SavableBaize = g.b.Position()
+
+	var history map[string]bool = make(map[string]bool)
+	for _, sav := range b.UndoStack() {
+		g.b.SetPosition(sav)
+		history[g.key()] = true
+	}
+
+	type ranked struct {
+		Move
+		score int
+	}
+	var hints []ranked
+	for _, m := range g.moves() {
+		g.b.SetPosition(start)
+		if !g.play(m) {
+			continue
+		}
+		var score int
+		if history[g.key()] {
+			score = -1
+		} else {
+			score = g.lookahead(hintDepth - 1)
+		}
+		hints = append(hints, ranked{Move: g.original(b, m), score: score})
+	}
+	// moves comes sorted by how good the move looks on its own, so keep that order for equal scores
+	sort.SliceStable(hints, func(i, j int) bool { return hints[i].score > hints[j].score })
+
+	var moves []Move = make([]Move, 0, len(hints))
+	if _, ok := variants[b.Variant()]; ok {
+		if result, solution, _ := solveFreecell(b, hintMaxNodes, time.Time{}); result == SOLVABLE && len(solution) > 0 {
+			moves = append(moves, solution[0])
+		}
+	}
+	for _, h := range hints {
+		if len(moves) > 0 && h.Move == moves[0] {
+			continue
+		}
+		moves = append(moves, h.Move)
+	}
+	return moves
+}
+
+// lookahead returns the best progress that can be made from the position on the copy
+// in depth more moves; the copy is left as it was found
+func (g *generic) lookahead(depth int) int {
+	if g.b.Complete() {
+		// the sooner the better
+		return 1000 + depth
+	}
+	var best int = g.progress()
+	if depth == 0 {
+		return best
+	}
+	var sav *core.SavableBaize = g.b.Position()
+	var moved bool
+	for _, m := range g.moves() {
+		if moved {
+			g.b.SetPosition(sav)
+		}
+		if moved = g.play(m); !moved {
+			continue
+		}
+		if score := g.lookahead(depth - 1); score > best {
+			best = score
+		}
+	}
+	if moved {
+		g.b.SetPosition(sav)
+	}
+	return best
+}
+
+// progress scores the position on the copy; bigger is better
+func (g *generic) progress() int {
+	var score int = g.b.PercentComplete()
+	for _, p := range g.b.Script().Foundations() {
+		score += p.Len()
+	}
+	for _, p := range g.b.Script().Discards() {
+		score += p.Len()
+	}
+	return score
+}
+
+// original returns a move between the piles of the copy as the same move between the piles of b;
+// the other way round too, when g is the game and b the copy
+func (g *generic) original(b *core.Baize, m Move) Move {
+	for i, p := range g.b.Piles() {
+		if p == m.Src {
+			m.Src = b.Piles()[i]
+		}
+		if m.Dst != nil && p == m.Dst {
+			m.Dst = b.Piles()[i]
+		}
+	}
+	return m
+}
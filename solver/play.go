@@ -0,0 +1,72 @@
+package solver
+
+import (
+	"fmt"
+
+	"oddstream.games/gosol/core"
+)
+
+// Play makes one move on b, as if a player had dragged or tapped the cards
+func Play(b *core.Baize, m Move) error {
+	if m.Tap() {
+		if m.Src.Empty() {
+			b.PileTapped(m.Src)
+		} else {
+			b.TailTapped([]*core.Card{m.Src.Peek()})
+		}
+	} else {
+		cards := m.Src.Cards()
+		if m.Count > len(cards) {
+			return fmt.Errorf("%s has %d cards, not %d", m.Src.Category(), len(cards), m.Count)
+		}
+		if err := b.MoveTailTo(cards[len(cards)-m.Count:], m.Dst); err != nil {
+			return err
+		}
+	}
+	b.AfterUserMove()
+	return nil
+}
+
+// AutoPlay plays the game on b the simple way, by making the best hint each time,
+// until it is won, maxMoves moves have been made, or every move leads back to a position
+// already seen. It returns the number of moves made. Each hint is tried on a copy first,
+// so b only gets the moves that are kept, and no undone ones.
+func AutoPlay(b *core.Baize, maxMoves int) int {
+	g := &generic{b: b}
+	try := &generic{b: b.Copy()}
+	var seen map[string]bool = map[string]bool{g.key(): true}
+	var moves int
+	for moves < maxMoves && !b.Complete() {
+		var played bool
+		for _, m := range Hints(b) {
+			try.b.SetPosition(b.Position())
+			if Play(try.b, g.original(try.b, m)) != nil || seen[try.key()] {
+				continue
+			}
+			if played = Play(b, m) == nil; played {
+				break
+			}
+		}
+		if !played {
+			break
+		}
+		seen[g.key()] = true
+		moves++
+	}
+	return moves
+}
+
+// SolvePlay plays the game on b by making the moves the solver finds, if it can find a win
+// looking at no more than maxNodes positions. It returns the number of moves made.
+func SolvePlay(b *core.Baize, maxNodes int) (int, error) {
+	result, moves, err := Solve(b, maxNodes)
+	if err != nil || result != SOLVABLE {
+		return 0, err
+	}
+	for i, m := range moves {
+		if err := Play(b, m); err != nil {
+			return i, err
+		}
+	}
+	return len(moves), nil
+}
@@ -0,0 +1,405 @@
+package solver
+
+import (
+	"sort"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// child is a position reached by making one move, and how promising it looks
+type child struct {
+	mv    move
+	pos   position
+	score int
+}
+
+// search does a depth first search from pos, trying the most promising moves first
+// and never looking at the same position twice.
+// It returns the moves that win the game, last move first, and true;
+// or false if no win was found, setting aborted if it ran out of positions to look at.
+func (s *solver) search(pos position) ([]move, bool) {
+	var auto []move
+	pos, auto = s.autoPlay(pos)
+	if s.won(pos) {
+		return reversed(auto), true
+	}
+	if s.aborted {
+		return nil, false
+	}
+	var key string = s.key(pos)
+	if _, ok := s.seen[key]; ok {
+		return nil, false
+	}
+	if len(s.seen) >= s.maxNodes || outOfTime(s.deadline, len(s.seen)) {
+		s.aborted = true
+		return nil, false
+	}
+	s.seen[key] = struct{}{}
+
+	var children []child
+	for _, mv := range s.moves(pos) {
+		next := s.apply(pos, mv)
+		children = append(children, child{mv: mv, pos: next, score: s.score(next)})
+	}
+	sort.SliceStable(children, func(i, j int) bool { return children[i].score > children[j].score })
+	for _, ch := range children {
+		if path, ok := s.search(ch.pos); ok {
+			path = append(path, ch.mv)
+			return append(path, reversed(auto)...), true
+		}
+		if s.aborted {
+			break
+		}
+	}
+	return nil, false
+}
+
+func reversed(moves []move) []move {
+	var r []move = make([]move, 0, len(moves))
+	for i := len(moves) - 1; i >= 0; i-- {
+		r = append(r, moves[i])
+	}
+	return r
+}
+
+func (s *solver) isCell(i int) bool {
+	return i < s.cells
+}
+
+func (s *solver) isFoundation(i int) bool {
+	return i >= s.cells && i < s.cells+s.foundations
+}
+
+func (s *solver) isTableau(i int) bool {
+	return i >= s.cells+s.foundations
+}
+
+func top(cards []card) card {
+	if len(cards) == 0 {
+		return 0
+	}
+	return cards[len(cards)-1]
+}
+
+// apply makes a new position by moving count cards from src to dst;
+// only the two piles that change are copied
+func (s *solver) apply(pos position, mv move) position {
+	var next position = make(position, len(pos))
+	copy(next, pos)
+	var src []card = pos[mv.src]
+	var n int = len(src) - mv.count
+	next[mv.src] = src[:n:n]
+	var dst []card = make([]card, 0, len(pos[mv.dst])+mv.count)
+	dst = append(dst, pos[mv.dst]...)
+	next[mv.dst] = append(dst, src[n:]...)
+	return next
+}
+
+// fits returns true if the upper card can be put on the lower card in a tableau
+func (s *solver) fits(lower, upper card) bool {
+	if upper.ordinal() != lower.ordinal()-1 {
+		return false
+	}
+	if s.buildSuit {
+		return upper.suit() == lower.suit()
+	}
+	return upper.black() != lower.black()
+}
+
+// runLength returns the number of cards at the top of a tableau that can be moved together
+func (s *solver) runLength(cards []card) int {
+	if len(cards) == 0 {
+		return 0
+	}
+	var n int = 1
+	for i := len(cards) - 1; i > 0 && s.fits(cards[i-1], cards[i]); i-- {
+		n++
+	}
+	return n
+}
+
+// homeOrdinal returns the ordinal of the top card of the foundation for suit, 0 if none
+func (s *solver) homeOrdinal(pos position, suit int) int {
+	for i := s.cells; i < s.cells+s.foundations; i++ {
+		if c := top(pos[i]); c != 0 && c.suit() == suit {
+			return c.ordinal()
+		}
+	}
+	return 0
+}
+
+// foundationFor returns the foundation that will accept c, or -1
+func (s *solver) foundationFor(pos position, c card) int {
+	for i := s.cells; i < s.cells+s.foundations; i++ {
+		t := top(pos[i])
+		if c.ordinal() == 1 && t == 0 {
+			return i
+		}
+		if t != 0 && t.suit() == c.suit() && t.ordinal() == c.ordinal()-1 {
+			return i
+		}
+	}
+	return -1
+}
+
+// safe returns true if no other card could ever want to be put on c,
+// so moving it to a foundation cannot be a mistake
+func (s *solver) safe(pos position, c card) bool {
+	if c.ordinal() <= 2 || s.buildSuit {
+		// when building in suit, the card that goes on c is already on a foundation
+		return true
+	}
+	for suit := cardid.CLUB; suit <= cardid.SPADE; suit++ {
+		if (suit == cardid.CLUB || suit == cardid.SPADE) != c.black() {
+			if s.homeOrdinal(pos, suit) < c.ordinal()-1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// autoPlay moves cards to the foundations while it is safe to do so
+func (s *solver) autoPlay(pos position) (position, []move) {
+	var moves []move
+	for {
+		var moved bool
+		for i := range pos {
+			if s.isFoundation(i) {
+				continue
+			}
+			c := top(pos[i])
+			if c == 0 || !s.safe(pos, c) {
+				continue
+			}
+			if f := s.foundationFor(pos, c); f != -1 {
+				mv := move{src: i, dst: f, count: 1}
+				pos = s.apply(pos, mv)
+				moves = append(moves, mv)
+				moved = true
+			}
+		}
+		if !moved {
+			return pos, moves
+		}
+	}
+}
+
+func (s *solver) won(pos position) bool {
+	for i, cards := range pos {
+		if !s.isFoundation(i) && len(cards) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// empties returns the number of empty cells, and of empty tableaux that accept any card
+func (s *solver) empties(pos position) (int, int) {
+	var cells, cols int
+	for i, cards := range pos {
+		if len(cards) == 0 {
+			if s.isCell(i) {
+				cells++
+			} else if s.isTableau(i) && s.accept[i] == 0 {
+				cols++
+			}
+		}
+	}
+	return cells, cols
+}
+
+// maxMove returns the number of cards that can be moved to tableau dst at once,
+// the same way as the rules do when power moves are allowed
+func (s *solver) maxMove(pos position, dst int) int {
+	if !s.powerMoves {
+		return 1
+	}
+	cells, cols := s.empties(pos)
+	if len(pos[dst]) == 0 && s.accept[dst] == 0 {
+		// moving into an empty column, that column does not count
+		cols--
+	}
+	return (1 + cells) << cols
+}
+
+// accepts returns true if the empty tableau dst will take a tail starting with c
+func (s *solver) accepts(dst int, c card) bool {
+	return s.accept[dst] == 0 || s.accept[dst] == c.ordinal()
+}
+
+// moves returns every move worth making from pos, roughly best first
+func (s *solver) moves(pos position) []move {
+	var moves []move
+
+	// cards to foundations that were not safe to move automatically
+	for i := range pos {
+		if s.isFoundation(i) {
+			continue
+		}
+		if c := top(pos[i]); c != 0 {
+			if f := s.foundationFor(pos, c); f != -1 {
+				moves = append(moves, move{src: i, dst: f, count: 1})
+			}
+		}
+	}
+
+	// cards onto other cards in the tableaux
+	for dst := range pos {
+		if !s.isTableau(dst) || len(pos[dst]) == 0 {
+			continue
+		}
+		d := top(pos[dst])
+		for src := range pos {
+			if src == dst || s.isFoundation(src) || len(pos[src]) == 0 {
+				continue
+			}
+			if s.isCell(src) {
+				if s.fits(d, top(pos[src])) {
+					moves = append(moves, move{src: src, dst: dst, count: 1})
+				}
+				continue
+			}
+			// the card in the run of src that would go on d
+			n := d.ordinal() - top(pos[src]).ordinal()
+			if n < 1 || n > s.runLength(pos[src]) || n > s.maxMove(pos, dst) {
+				continue
+			}
+			if s.fits(d, pos[src][len(pos[src])-n]) {
+				moves = append(moves, move{src: src, dst: dst, count: n})
+			}
+		}
+	}
+
+	// cards into empty tableaux; all the empty tableaux that take the same cards are alike, so only try one
+	var tried map[int]bool = make(map[int]bool)
+	for dst := range pos {
+		if !s.isTableau(dst) || len(pos[dst]) > 0 || tried[s.accept[dst]] {
+			continue
+		}
+		tried[s.accept[dst]] = true
+		for src := range pos {
+			if src == dst || s.isFoundation(src) || len(pos[src]) == 0 {
+				continue
+			}
+			if s.isCell(src) {
+				if s.accepts(dst, top(pos[src])) {
+					moves = append(moves, move{src: src, dst: dst, count: 1})
+				}
+				continue
+			}
+			var run int = s.runLength(pos[src])
+			if max := s.maxMove(pos, dst); run > max {
+				run = max
+			}
+			for n := run; n > 0; n-- {
+				if n == len(pos[src]) && s.accept[src] == s.accept[dst] {
+					// moving a whole column to a column just like it
+					continue
+				}
+				if s.accepts(dst, pos[src][len(pos[src])-n]) {
+					moves = append(moves, move{src: src, dst: dst, count: n})
+				}
+			}
+		}
+	}
+
+	// cards into a cell; all empty cells are alike, so only try the first one
+	for dst := range pos {
+		if s.isCell(dst) && len(pos[dst]) == 0 {
+			for src := range pos {
+				if s.isTableau(src) && len(pos[src]) > 0 {
+					moves = append(moves, move{src: src, dst: dst, count: 1})
+				}
+			}
+			break
+		}
+	}
+
+	return moves
+}
+
+// score guesses how close pos is to being won; bigger is better
+func (s *solver) score(pos position) int {
+	var score int
+	cells, cols := s.empties(pos)
+	score += cells*2 + cols*4
+	var home [cardid.SPADE + 1]int
+	for suit := cardid.CLUB; suit <= cardid.SPADE; suit++ {
+		home[suit] = s.homeOrdinal(pos, suit)
+		score += home[suit] * 5
+	}
+	for i, cards := range pos {
+		if !s.isTableau(i) {
+			continue
+		}
+		for j, c := range cards {
+			if j > 0 && !s.fits(cards[j-1], c) {
+				// a card sitting on a card it does not build on is in the way
+				score--
+			}
+			if c.ordinal() == home[c.suit()]+1 {
+				// the cards covering a card that could go to a foundation are in the way
+				score -= len(cards) - 1 - j
+			}
+		}
+	}
+	return score
+}
+
+// key makes a string that is the same for positions that only differ in the
+// order of their cells, foundations or (alike) tableaux
+func (s *solver) key(pos position) string {
+	var buf []byte = make([]byte, 0, 128)
+	for suit := cardid.CLUB; suit <= cardid.SPADE; suit++ {
+		buf = append(buf, byte(s.homeOrdinal(pos, suit)))
+	}
+	var start int = len(buf)
+	for i := 0; i < s.cells; i++ {
+		if len(pos[i]) > 0 {
+			buf = append(buf, byte(pos[i][0]))
+		}
+	}
+	cells := buf[start:]
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+	buf = append(buf, 0xff)
+
+	var cols []int = make([]int, 0, len(pos)-s.cells-s.foundations)
+	for i := s.cells + s.foundations; i < len(pos); i++ {
+		cols = append(cols, i)
+	}
+	sort.Slice(cols, func(i, j int) bool {
+		a, b := cols[i], cols[j]
+		if s.accept[a] != s.accept[b] {
+			return s.accept[a] < s.accept[b]
+		}
+		return compare(pos[a], pos[b]) < 0
+	})
+	for _, i := range cols {
+		buf = append(buf, byte(s.accept[i]))
+		for _, c := range pos[i] {
+			buf = append(buf, byte(c))
+		}
+		buf = append(buf, 0xfe)
+	}
+	return string(buf)
+}
+
+// compare returns -1, 0 or 1 as a sorts before, the same as, or after b
+func compare(a, b []card) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,186 @@
+// Package solver searches for a way to win a game from its current position.
+// The open Freecell-like games, where every card can be seen and cards only move between
+// tableaux, cells and foundations, have a fast solver of their own; every other variant
+// is searched by playing it with its own rules
+package solver
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"oddstream.games/gosol/cardid"
+	"oddstream.games/gosol/core"
+	"oddstream.games/gosol/util"
+)
+
+// Result says what the search found out about a position
+type Result int
+
+const (
+	UNKNOWN    Result = iota // gave up after looking at the maximum number of positions
+	SOLVABLE                 // found a way to put every card on the foundations
+	UNSOLVABLE               // looked at every reachable position, and none of them are won
+)
+
+func (r Result) String() string {
+	switch r {
+	case SOLVABLE:
+		return "solvable"
+	case UNSOLVABLE:
+		return "unsolvable"
+	}
+	return "unknown"
+}
+
+// DefaultMaxNodes returns the number of positions that can be looked at in a second or so,
+// which is enough to decide most Freecell deals, but only the easier deals of other variants
+func DefaultMaxNodes(b *core.Baize) int {
+	if _, ok := variants[b.Variant()]; ok {
+		return 100000
+	}
+	// playing with the variant's own rules is much slower
+	return 5000
+}
+
+// variants maps the names of the variants the Freecell solver knows to how their tableaux build;
+// true means down in suit, false means down in alternate colors
+var variants = map[string]bool{
+	"Freecell":        false,
+	"Freecell Easy":   false,
+	"Baker's Game":    true,
+	"Eight Off":       true,
+	"Seahaven Towers": true,
+}
+
+// Move is one step of a solution; Count cards are taken from the top of Src and put on Dst.
+// If Dst is nil, the Src pile (a Stock) is tapped instead.
+type Move struct {
+	Src, Dst *core.Pile
+	Count    int
+}
+
+// Tap returns true if this move is made by tapping the Src pile,
+// or the top card of it, if it has any
+func (m Move) Tap() bool {
+	return m.Dst == nil
+}
+
+func (m Move) String() string {
+	if m.Tap() {
+		return fmt.Sprintf("tap %s", m.Src.Category())
+	}
+	return fmt.Sprintf("%d from %s to %s", m.Count, m.Src.Category(), m.Dst.Category())
+}
+
+// card is a cardid.CardID crammed into a byte, suit in the high nibble, ordinal in the low
+type card byte
+
+func (c card) suit() int    { return int(c >> 4) }
+func (c card) ordinal() int { return int(c & 0xf) }
+func (c card) black() bool  { return c.suit() == cardid.CLUB || c.suit() == cardid.SPADE }
+
+// move is a Move between the solver's own piles, which are numbered cells first,
+// then foundations, then tableaux
+type move struct {
+	src, dst, count int
+}
+
+// position holds the cards in each of the solver's piles;
+// a pile may share its cards with other positions, so is never appended to in place
+type position [][]card
+
+type solver struct {
+	piles       []*core.Pile
+	cells       int // number of cells; they are piles 0 .. cells-1
+	foundations int // number of foundations; they follow the cells
+	accept      []int
+	buildSuit   bool
+	powerMoves  bool
+	maxNodes    int
+	deadline    time.Time // when to give up; zero for never
+	seen        map[string]struct{}
+	aborted     bool
+}
+
+// Solve searches for a way to win from the current position on b, looking at no more
+// than maxNodes different positions. The Baize is not changed.
+// If the result is SOLVABLE, the moves that win the game are also returned.
+func Solve(b *core.Baize, maxNodes int) (Result, []Move, error) {
+	return solveBy(b, maxNodes, time.Time{})
+}
+
+// solveBy is Solve, giving up with UNKNOWN at the deadline if it is not zero
+func solveBy(b *core.Baize, maxNodes int, deadline time.Time) (Result, []Move, error) {
+	if _, ok := variants[b.Variant()]; !ok {
+		return solveGeneric(b, maxNodes, deadline)
+	}
+	return solveFreecell(b, maxNodes, deadline)
+}
+
+// outOfTime returns true if the deadline has passed; the clock is only looked at every few hundred positions
+func outOfTime(deadline time.Time, nodes int) bool {
+	return !deadline.IsZero() && nodes%256 == 0 && time.Now().After(deadline)
+}
+
+// solveFreecell is the fast solver for the variants it knows
+func solveFreecell(b *core.Baize, maxNodes int, deadline time.Time) (Result, []Move, error) {
+	buildSuit, ok := variants[b.Variant()]
+	if !ok {
+		return UNKNOWN, nil, fmt.Errorf("Cannot solve %s", b.Variant())
+	}
+	s := &solver{
+		buildSuit:  buildSuit,
+		powerMoves: b.Options().PowerMoves,
+		maxNodes:   maxNodes,
+		deadline:   deadline,
+		seen:       make(map[string]struct{}),
+	}
+	script := b.Script()
+	s.cells = len(script.Cells())
+	s.foundations = len(script.Foundations())
+	s.piles = append(s.piles, script.Cells()...)
+	s.piles = append(s.piles, script.Foundations()...)
+	s.piles = append(s.piles, script.Tableaux()...)
+
+	var pos position = make(position, len(s.piles))
+	s.accept = make([]int, len(s.piles))
+	for i, p := range s.piles {
+		for _, c := range p.Cards() {
+			if c.Prone() {
+				return UNKNOWN, nil, errors.New("Cannot solve a game with face down cards")
+			}
+			pos[i] = append(pos[i], card(c.ID().PackSuitOrdinal()))
+		}
+		if i >= s.cells+s.foundations && p.Label() != "" {
+			s.accept[i] = labelOrdinal(p.Label())
+		}
+	}
+
+	path, ok := s.search(pos)
+	if ok {
+		var moves []Move = make([]Move, 0, len(path))
+		for i := len(path) - 1; i >= 0; i-- {
+			m := path[i]
+			moves = append(moves, Move{Src: s.piles[m.src], Dst: s.piles[m.dst], Count: m.count})
+		}
+		return SOLVABLE, moves, nil
+	}
+	if s.aborted {
+		return UNKNOWN, nil, nil
+	}
+	return UNSOLVABLE, nil, nil
+}
+
+// labelOrdinal turns a pile label like "K" into the ordinal of the only card
+// an empty pile accepts; a label like "X" means it accepts nothing
+func labelOrdinal(label string) int {
+	for ord := 1; ord <= 13; ord++ {
+		if util.OrdinalToShortString(ord) == label {
+			return ord
+		}
+	}
+	return -1
+}
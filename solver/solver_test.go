@@ -0,0 +1,245 @@
+package solver
+
+import (
+	"testing"
+	"time"
+
+	"oddstream.games/gosol/core"
+)
+
+// replay makes the moves of a solution on b, as if a player had dragged the cards
+func replay(t *testing.T, b *core.Baize, moves []Move) {
+	for i, m := range moves {
+		if err := Play(b, m); err != nil {
+			t.Fatalf("move %d (%s): %s", i, m, err)
+		}
+	}
+	if !b.Complete() {
+		t.Errorf("%s not complete after %d moves", b.Variant(), len(moves))
+	}
+}
+
+func microsoftDeal(t *testing.T, number uint64, options *core.Options) *core.Baize {
+	b := core.NewBaize("Freecell", options)
+	b.StartFreshGame(core.NewSeed())
+	if err := b.NewMicrosoftDeal(number); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestMicrosoftDeals checks the solver against deals whose fate is well known
+func TestMicrosoftDeals(t *testing.T) {
+	b := microsoftDeal(t, 1, nil)
+	result, moves, err := Solve(b, DefaultMaxNodes(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != SOLVABLE {
+		t.Fatalf("deal 1 is %s", result)
+	}
+	replay(t, b, moves)
+
+	// the famous one that cannot be won
+	b = microsoftDeal(t, 11982, nil)
+	if result, _, _ = Solve(b, 1000000); result != UNSOLVABLE {
+		t.Errorf("deal 11982 is %s", result)
+	}
+
+	// a budget too small to decide anything
+	b = microsoftDeal(t, 11982, nil)
+	if result, _, _ = Solve(b, 10); result != UNKNOWN {
+		t.Errorf("deal 11982 in 10 positions is %s", result)
+	}
+}
+
+// TestWithoutPowerMoves checks that only single cards are moved when the rules say so
+func TestWithoutPowerMoves(t *testing.T) {
+	b := microsoftDeal(t, 1, &core.Options{PowerMoves: false})
+	result, moves, _ := Solve(b, DefaultMaxNodes(b))
+	if result != SOLVABLE {
+		t.Fatalf("deal 1 without power moves is %s", result)
+	}
+	for i, m := range moves {
+		if m.Count != 1 {
+			t.Fatalf("move %d moves %d cards", i, m.Count)
+		}
+	}
+	replay(t, b, moves)
+}
+
+// TestVariants solves a few deals of each variant from part way through a game
+func TestVariants(t *testing.T) {
+	// deals that are known to be solvable
+	var deals map[string][]uint64 = map[string][]uint64{
+		"Freecell":        {1, 2, 3},
+		"Baker's Game":    {2, 4, 5},
+		"Eight Off":       {1, 2, 3},
+		"Seahaven Towers": {2, 3, 4},
+	}
+	for name, seeds := range deals {
+		for _, seed := range seeds {
+			b := core.NewBaize(name, nil)
+			b.StartFreshGame(seed)
+			result, moves, err := Solve(b, DefaultMaxNodes(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != SOLVABLE {
+				t.Errorf("%s deal %d is %s", name, seed, result)
+				continue
+			}
+			// play a few moves, then solve again from there
+			var half int = len(moves) / 2
+			for _, m := range moves[:half] {
+				if err := Play(b, m); err != nil {
+					t.Fatalf("%s deal %d: %s", name, seed, err)
+				}
+			}
+			result, moves, _ = Solve(b, DefaultMaxNodes(b))
+			if result != SOLVABLE {
+				t.Fatalf("%s deal %d is %s half way through a solution", name, seed, result)
+			}
+			replay(t, b, moves)
+		}
+	}
+}
+
+// TestGeneric solves some easy deals of other variants by playing them with their own rules
+func TestGeneric(t *testing.T) {
+	for _, easy := range []struct {
+		name string
+		seed uint64
+	}{{"Klondike", 1}, {"Canfield", 2}, {"Bisley", 1}, {"Agnes Bernauer", 1}, {"Australian", 3}, {"Duchess", 1}} {
+		b := core.NewBaize(easy.name, nil)
+		b.StartFreshGame(easy.seed)
+		result, moves, err := Solve(b, DefaultMaxNodes(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != SOLVABLE {
+			t.Errorf("%s deal %d is %s", easy.name, easy.seed, result)
+			continue
+		}
+		replay(t, b, moves)
+	}
+
+	// the generic solver should agree with the Freecell solver
+	b := core.NewBaize("Eight Off", nil)
+	b.StartFreshGame(1)
+	result, moves, _ := solveGeneric(b, 20000, time.Time{})
+	if result != SOLVABLE {
+		t.Fatalf("Eight Off deal 1 is %s", result)
+	}
+	replay(t, b, moves)
+}
+
+// TestAllVariants gives every variant a quick look, which should not upset any of them
+func TestAllVariants(t *testing.T) {
+	for name := range core.Variants {
+		b := core.NewBaize(name, nil)
+		b.StartFreshGame(core.NewSeed())
+		crc := b.CRC()
+		if _, _, err := Solve(b, 50); err != nil {
+			t.Errorf("%s: %s", name, err)
+		}
+		if crc != b.CRC() || len(b.UndoStack()) != 1 {
+			t.Errorf("%s: solving changed the game", name)
+		}
+	}
+}
+
+// TestHints checks that every hint is a move that can be made, and the best one is good
+func TestHints(t *testing.T) {
+	for _, name := range []string{"Freecell", "Klondike", "Spider One Suit"} {
+		b := core.NewBaize(name, nil)
+		b.StartFreshGame(1)
+		hints := Hints(b)
+		if len(hints) == 0 {
+			t.Fatalf("%s: no hints", name)
+		}
+		for _, h := range hints {
+			cp := b.Copy()
+			if err := Play(cp, Move{Src: copyPile(b, cp, h.Src), Dst: copyPile(b, cp, h.Dst), Count: h.Count}); err != nil {
+				t.Errorf("%s: hint %s cannot be played: %s", name, h, err)
+			}
+		}
+		if len(b.UndoStack()) != 1 {
+			t.Errorf("%s: hints changed the game", name)
+		}
+	}
+
+	// with a win in sight, the first hint should be the first move of it
+	b := microsoftDeal(t, 1, nil)
+	_, moves, _ := Solve(b, hintMaxNodes)
+	if hints := Hints(b); hints[0] != moves[0] {
+		t.Errorf("first hint is %s, not %s", hints[0], moves[0])
+	}
+}
+
+func index(b *core.Baize, p *core.Pile) int {
+	for i, bp := range b.Piles() {
+		if bp == p {
+			return i
+		}
+	}
+	return -1
+}
+
+func copyPile(b, cp *core.Baize, p *core.Pile) *core.Pile {
+	if p == nil {
+		return nil
+	}
+	return cp.Piles()[index(b, p)]
+}
+
+// TestAutoPlay checks the simple player makes legal moves, never goes round in circles, and can win an easy game
+func TestAutoPlay(t *testing.T) {
+	for _, name := range []string{"Klondike", "Freecell Easy", "Spider One Suit"} {
+		b := core.NewBaize(name, nil)
+		b.StartFreshGame(3)
+		moves := AutoPlay(b, 200)
+		if len(b.UndoStack()) != moves+1 {
+			t.Errorf("%s: %d moves made, %d positions", name, moves, len(b.UndoStack()))
+		}
+		if n := len(b.Branches()); n != 1 {
+			t.Errorf("%s: %d branches after playing", name, n)
+		}
+		var seen map[string]bool = make(map[string]bool)
+		g := &generic{b: b.Copy()}
+		for _, sav := range b.UndoStack() {
+			g.b.SetPosition(sav)
+			if seen[g.key()] {
+				t.Errorf("%s: went back to a position", name)
+			}
+			seen[g.key()] = true
+		}
+	}
+	b := core.NewBaize("Freecell Easy", nil)
+	b.StartFreshGame(2)
+	if AutoPlay(b, 500); !b.Complete() {
+		t.Error("Freecell Easy deal 2 not won")
+	}
+}
+
+// TestFindWinnableDeal checks that the deal found can be won, and that a short timeout gives up
+func TestFindWinnableDeal(t *testing.T) {
+	seed, ok := FindWinnableDeal("Baker's Game", nil, time.Minute)
+	if !ok {
+		t.Fatal("no winnable deal of Baker's Game")
+	}
+	b := core.NewBaize("Baker's Game", nil)
+	b.StartFreshGame(seed)
+	if result, _, _ := Solve(b, DefaultMaxNodes(b)); result != SOLVABLE {
+		t.Errorf("deal %d is %s", seed, result)
+	}
+	if _, ok := FindWinnableDeal("Spider Four Suits", nil, 0); ok {
+		t.Error("found a winnable deal of Spider Four Suits without any time")
+	}
+	// the deadline stops a search part way through a deal
+	var start time.Time = time.Now()
+	FindWinnableDeal("Spider Four Suits", nil, 100*time.Millisecond)
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("gave up after %s, not 100ms", d)
+	}
+}
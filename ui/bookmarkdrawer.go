@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"oddstream.games/gosol/schriftbank"
+)
+
+// BookmarkDrawer slide out modal drawer listing the bookmarks of the current game
+type BookmarkDrawer struct {
+	DrawerBase
+}
+
+// NewBookmarkDrawer creates the BookmarkDrawer object; it starts life off screen to the left
+func NewBookmarkDrawer() *BookmarkDrawer {
+	d := &BookmarkDrawer{DrawerBase: DrawerBase{WindowBase: WindowBase{width: 300, height: 0, x: -300, y: ToolbarHeight}}}
+	return d
+}
+
+// ShowBookmarkDrawer makes the bookmark drawer visible; each bookmark is shown by name,
+// which can be tapped to go to it, with a summary of the position and a delete button underneath
+func (u *UI) ShowBookmarkDrawer(names []string, summaries []string) {
+	u.bookmarkDrawer.widgets = u.bookmarkDrawer.widgets[:0]
+	if len(names) == 0 {
+		u.bookmarkDrawer.widgets = append(u.bookmarkDrawer.widgets, NewText(u.bookmarkDrawer, "", "No bookmarks yet; press S to bookmark a position"))
+	}
+	for i, name := range names {
+		del := NewLabel(u.bookmarkDrawer, "", 0, "Delete", schriftbank.RobotoRegular14, "DeleteBookmark")
+		del.data = name
+		u.bookmarkDrawer.widgets = append(u.bookmarkDrawer.widgets,
+			NewLabel(u.bookmarkDrawer, "", 0, name, schriftbank.RobotoMedium24, "GotoBookmark"),
+			NewText(u.bookmarkDrawer, "", summaries[i]),
+			del)
+	}
+	u.bookmarkDrawer.ResetScroll()
+	u.bookmarkDrawer.LayoutWidgets()
+	u.bookmarkDrawer.Show()
+}
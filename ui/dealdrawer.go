@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"oddstream.games/gosol/schriftbank"
+)
+
+// the largest uint64 has 20 digits
+const maxDealDigits = 20
+
+// DealDrawer slide out modal drawer for typing the number of a deal to play
+type DealDrawer struct {
+	DrawerBase
+	digits  string
+	replace bool // the first key typed replaces the number shown
+}
+
+// NewDealDrawer creates the DealDrawer object; it starts life off screen to the left
+func NewDealDrawer() *DealDrawer {
+	d := &DealDrawer{DrawerBase: DrawerBase{WindowBase: WindowBase{width: 360, height: 0, x: -400, y: ToolbarHeight}}}
+	return d
+}
+
+// ShowDealDrawer makes the deal drawer visible, showing the current deal number;
+// if msDeals is not nil, a checkbox lets the player choose Microsoft FreeCell deal numbers
+func (u *UI) ShowDealDrawer(current string, msDeals *bool) {
+	con := u.VisibleDrawer()
+	if con == u.dealDrawer {
+		return
+	}
+	if con != nil {
+		con.Hide()
+	}
+	u.dealDrawer.digits = current
+	u.dealDrawer.replace = true
+	u.dealDrawer.widgets = []Widgety{
+		// widget x, y will be set by LayoutWidgets()
+		NewText(u.dealDrawer, "", "Type a deal number, then press Enter or tap the number"),
+		NewLabel(u.dealDrawer, "dealNumber", 0, current, schriftbank.RobotoMedium24, "DealNumber"),
+	}
+	if msDeals != nil {
+		u.dealDrawer.widgets = append(u.dealDrawer.widgets, NewCheckbox(u.dealDrawer, "", "Microsoft numbers", msDeals, nil))
+	}
+	u.dealDrawer.LayoutWidgets()
+	u.dealDrawer.Show()
+}
+
+// CapturingKeys returns true if a drawer wants the keyboard for itself,
+// so key presses should not be treated as commands
+func (u *UI) CapturingKeys() bool {
+	return u.dealDrawer.Visible()
+}
+
+func (d *DealDrawer) updateDigits(digits string) {
+	d.digits = digits
+	if l, ok := d.widgets[1].(*Label); ok {
+		l.UpdateText(d.digits)
+	}
+}
+
+// Update the DealDrawer, taking digits from the keyboard while it is visible
+func (d *DealDrawer) Update() {
+	d.DrawerBase.Update()
+	if !d.Visible() {
+		return
+	}
+	var digits string = d.digits
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if r >= '0' && r <= '9' {
+			if d.replace {
+				digits, d.replace = "", false
+			}
+			if len(digits) < maxDealDigits {
+				digits += string(r)
+			}
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(digits) > 0 {
+		if d.replace {
+			digits, d.replace = "", false
+		} else {
+			digits = digits[:len(digits)-1]
+		}
+	}
+	if digits != d.digits {
+		d.updateDigits(digits)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		cmdFn(Command{Command: "DealNumber", Data: d.digits})
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		d.Hide()
+	}
+}
@@ -60,6 +60,15 @@ var starIconBytes []byte
 //go:embed icons/undo.png
 var undoIconBytes []byte
 
+//go:embed icons/redo.png
+var redoIconBytes []byte
+
+//go:embed icons/play_arrow.png
+var play_arrowIconBytes []byte
+
+//go:embed icons/pause.png
+var pauseIconBytes []byte
+
 //go:embed icons/lightbulb.png
 var lightbulbIconBytes []byte
 
@@ -69,6 +78,9 @@ var pollIconBytes []byte
 //go:embed icons/speed.png
 var speedIconBytes []byte
 
+//go:embed icons/palette.png
+var paletteIconBytes []byte
+
 //go:embed icons/wikipedia.png
 var wikipediaIconBytes []byte
 
@@ -101,8 +113,12 @@ func LoadIconMapFromEmbedded() {
 	decode("settings", settingsIconBytes)
 	decode("star", starIconBytes)
 	decode("undo", undoIconBytes)
+	decode("redo", redoIconBytes)
+	decode("play_arrow", play_arrowIconBytes)
+	decode("pause", pauseIconBytes)
 	decode("lightbulb", lightbulbIconBytes)
 	decode("poll", pollIconBytes)
 	decode("speed", speedIconBytes)
+	decode("palette", paletteIconBytes)
 	decode("wikipedia", wikipediaIconBytes)
 }
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Drawers can be used without a mouse, eg with a gamepad: Up and Down move the focus between
+// the widgets of the open drawer, Space or Enter taps the widget with the focus, and Escape closes the drawer
+
+// DrawerKey moves about the open drawer, returning true if the key was used for that
+func (u *UI) DrawerKey(key ebiten.Key) bool {
+	con := u.VisibleDrawer()
+	if con == nil {
+		return false
+	}
+	switch key {
+	case ebiten.KeyUp:
+		u.moveFocus(con, -1)
+	case ebiten.KeyDown:
+		u.moveFocus(con, 1)
+	case ebiten.KeySpace, ebiten.KeyEnter:
+		if u.focus == nil || u.focus.Parent() != con {
+			u.moveFocus(con, 1) // show where the focus is first
+		} else {
+			u.focus.Tapped()
+		}
+	case ebiten.KeyEscape:
+		u.focus = nil
+		con.Hide()
+	default:
+		return false
+	}
+	return true
+}
+
+// moveFocus moves the focus to the next (dir 1) or previous (dir -1) widget of con that is not disabled,
+// scrolling the drawer so the widget can be seen
+func (u *UI) moveFocus(con Containery, dir int) {
+	var widgets []Widgety = con.Widgets()
+	var i int = -1
+	for j, w := range widgets {
+		if w == u.focus {
+			i = j
+		}
+	}
+	if i == -1 && dir < 0 {
+		i = len(widgets)
+	}
+	for i += dir; i >= 0 && i < len(widgets); i += dir {
+		if !widgets[i].Disabled() {
+			u.focus = widgets[i]
+			break
+		}
+	}
+	if u.focus == nil || u.focus.Parent() != con {
+		return
+	}
+	if u.announcer != nil {
+		u.announcer(spokenWidget(u.focus))
+	}
+	const padding = 24
+	_, y0, _, y1 := u.focus.Rect()
+	_, height := con.Size()
+	var dy int
+	if y0 < padding {
+		dy = padding - y0
+	} else if y1 > height-padding {
+		dy = height - padding - y1
+	}
+	if dy != 0 {
+		con.StartDrag()
+		con.DragBy(0, dy)
+		con.StopDrag()
+	}
+}
+
+// drawFocus outlines the widget with the focus, if its drawer is open
+func (u *UI) drawFocus(screen *ebiten.Image) {
+	if u.focus == nil || u.focus.Parent() != u.VisibleDrawer() {
+		return
+	}
+	x0, y0, x1, y1 := u.focus.OffsetRect()
+	vector.StrokeRect(screen, float32(x0-4), float32(y0-4), float32(x1-x0+8), float32(y1-y0+8), 2, ForegroundColor, true)
+}
+
+// spokenWidget describes a widget for a screen reader
+func spokenWidget(w Widgety) string {
+	switch w := w.(type) {
+	case *NavItem:
+		return w.text
+	case *Label:
+		return w.text
+	case *Text:
+		return w.text
+	case *Checkbox:
+		if *w.boolVarPtr {
+			return w.text + ", on"
+		}
+		return w.text + ", off"
+	case *RadioButton:
+		if *w.floatVarPtr == w.value {
+			return w.text + ", chosen"
+		}
+		return w.text
+	}
+	return w.ID()
+}
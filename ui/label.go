@@ -12,6 +12,7 @@ type Label struct {
 	text     string
 	fontFace font.Face
 	command  string
+	data     string // sent with the command instead of the text, if set
 }
 
 func (l *Label) createImg() *ebiten.Image {
@@ -71,7 +72,11 @@ func (l *Label) Tapped() {
 		return
 	}
 	if l.command != "" {
-		cmdFn(Command{Command: l.command, Data: l.text})
+		var data string = l.text
+		if l.data != "" {
+			data = l.data
+		}
+		cmdFn(Command{Command: l.command, Data: data})
 	}
 }
 
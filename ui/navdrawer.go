@@ -23,12 +23,19 @@ func NewNavDrawer() *NavDrawer {
 		// widget x, y will be set by LayoutWidgets()
 		NewNavItem(nd, "newDeal", "star", "New deal", ebiten.KeyN),
 		NewNavItem(nd, "restartDeal", "restore", "Restart deal", ebiten.KeyR),
+		NewNavItem(nd, "dealNumber", "list", "Deal number...", ebiten.KeyD),
 		NewNavItem(nd, "findGame", "search", "Find game...", ebiten.KeyF),
 		NewNavItem(nd, "bookmark", "bookmark_add", "Set bookmark", ebiten.KeyS),
-		NewNavItem(nd, "gotoBookmark", "bookmark", "Go to bookmark", ebiten.KeyL),
+		NewNavItem(nd, "bookmarks", "bookmark", "Bookmarks...", ebiten.KeyL),
+		NewNavItem(nd, "branches", "undo", "Branches...", ebiten.KeyT),
+		NewNavItem(nd, "replays", "play_arrow", "Replays...", ebiten.KeyP),
+		NewNavItem(nd, "export", "list", "Export position", ebiten.KeyE),
+		NewNavItem(nd, "winnable", "info", "Is this still winnable?", ebiten.KeyW),
 		NewNavItem(nd, "wikipedia", "wikipedia", "Wikipedia...", ebiten.KeyF1),
 		NewNavItem(nd, "statistics", "poll", "Statistics...", ebiten.KeyF2),
 		NewNavItem(nd, "settings", "settings", "Settings...", ebiten.KeyF3),
+		NewNavItem(nd, "themes", "palette", "Themes...", ebiten.KeyK),
+		NewNavItem(nd, "cardPacks", "palette", "Card packs...", ebiten.KeyG),
 	}
 	// don't know how to ask a browser window to close
 	if runtime.GOARCH != "wasm" {
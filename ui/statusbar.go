@@ -22,6 +22,7 @@ func NewStatusbar() *Statusbar {
 		NewLabel(sb, "statusbarWaste", -1, "", schriftbank.RobotoRegular14, ""),  // 1 waste
 		NewLabel(sb, "statusbarMiddle", 0, "", schriftbank.RobotoRegular14, ""),  // 2 middle (debug)
 		NewLabel(sb, "statusbarPercent", 1, "", schriftbank.RobotoRegular14, ""), // 3 percent
+		NewLabel(sb, "statusbarDeal", 1, "", schriftbank.RobotoRegular14, ""),    // 4 deal number
 	}
 	return sb
 }
@@ -66,6 +67,17 @@ func (u *UI) SetPercent(percent int) {
 	u.statusbar.LayoutWidgets()
 }
 
+// SetDeal of the statusbar
+func (u *UI) SetDeal(deal string) {
+	var l *Label = u.statusbar.widgets[4].(*Label)
+	if deal == "" {
+		l.UpdateText("")
+	} else {
+		l.UpdateText("DEAL: " + deal)
+	}
+	u.statusbar.LayoutWidgets()
+}
+
 // Layout implements Ebiten's Layout
 func (sb *Statusbar) Layout(outsideWidth, outsideHeight int) (int, int) {
 	// override BarBase.Layout to get screen height and position statusbar
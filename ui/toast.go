@@ -35,6 +35,11 @@ func (u *UI) Toast(soundEffect string, message string) {
 	// play the sound even if the toast is already displayed
 	sound.Play(soundEffect)
 
+	// and say it again, for players who cannot see it
+	if u.announcer != nil {
+		u.announcer(message)
+	}
+
 	// if we are already displaying this message, reset ticksLeft and quit
 	// otherwise you can fill the screen with "Nothing to undo"
 	for _, t := range u.toastManager.toasts {
@@ -75,6 +80,11 @@ func (u *UI) Toast(soundEffect string, message string) {
 	u.toastManager.Add(t)
 }
 
+// SetAnnouncer sets a func to be told the message of every toast, to put it into words for screen readers
+func (u *UI) SetAnnouncer(fn func(string)) {
+	u.announcer = fn
+}
+
 func (u *UI) ToastError(message string) {
 	u.Toast("Error", message)
 }
@@ -20,6 +20,7 @@ func NewToolbar() *Toolbar {
 		NewIconButton(tb, "openMenu", 0, 0, 48, 48, -1, "menu", ebiten.KeyMenu),
 		NewLabel(tb, "toolbarTitle", 0, "title", schriftbank.RobotoMedium24, ""),
 		NewIconButton(tb, "toolbarUndo", 0, 0, 48, 48, 1, "undo", ebiten.KeyU),      // U for Undo
+		NewIconButton(tb, "toolbarRedo", 0, 0, 48, 48, 1, "redo", ebiten.KeyY),      // Y for redo, as in Ctrl+Y
 		NewIconButton(tb, "toolbarCollect", 0, 0, 48, 48, 1, "done", ebiten.KeyC),   // C for Collect
 		NewIconButton(tb, "toolbarHint", 0, 0, 48, 48, 1, "lightbulb", ebiten.KeyH), // H for Hint
 	}
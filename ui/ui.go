@@ -32,10 +32,14 @@ type UI struct {
 	settingsDrawer, aniSpeedDrawer *SettingsDrawer
 	variantPicker                  *Picker
 	textDrawer                     *TextDrawer
+	dealDrawer                     *DealDrawer
+	bookmarkDrawer                 *BookmarkDrawer
 	containers                     []Containery // all the containers
 	bars                           []Containery // just the status, toolbar, fab
 	drawers                        []Containery // just the drawers
 	toastManager                   *ToastManager
+	announcer                      func(string) // also told the message of every toast
+	focus                          Widgety      // the widget of the open drawer that Space or Enter taps
 }
 
 var cmdFn func(interface{})
@@ -55,11 +59,13 @@ func New(fn func(interface{})) *UI {
 	ui.settingsDrawer = NewSettingsDrawer()
 	ui.aniSpeedDrawer = NewSettingsDrawer()
 	ui.variantPicker = NewVariantPicker()
-	ui.textDrawer = NewTextDrawer() // contents are added when shown
+	ui.textDrawer = NewTextDrawer()         // contents are added when shown
+	ui.dealDrawer = NewDealDrawer()         // contents are added when shown
+	ui.bookmarkDrawer = NewBookmarkDrawer() // contents are added when shown
 
 	ui.bars = []Containery{ui.toolbar, ui.statusbar, ui.fab}
-	ui.drawers = []Containery{ui.navDrawer, ui.settingsDrawer, ui.aniSpeedDrawer, ui.variantPicker, ui.textDrawer}
-	ui.containers = []Containery{ui.toolbar, ui.statusbar, ui.fab, ui.navDrawer, ui.settingsDrawer, ui.aniSpeedDrawer, ui.variantPicker, ui.textDrawer}
+	ui.drawers = []Containery{ui.navDrawer, ui.settingsDrawer, ui.aniSpeedDrawer, ui.variantPicker, ui.textDrawer, ui.dealDrawer, ui.bookmarkDrawer}
+	ui.containers = []Containery{ui.toolbar, ui.statusbar, ui.fab, ui.navDrawer, ui.settingsDrawer, ui.aniSpeedDrawer, ui.variantPicker, ui.textDrawer, ui.dealDrawer, ui.bookmarkDrawer}
 
 	return ui
 }
@@ -154,5 +160,6 @@ func (u *UI) Draw(screen *ebiten.Image) {
 	for _, con := range u.containers {
 		con.Draw(screen)
 	}
+	u.drawFocus(screen)
 	u.toastManager.Draw(screen)
 }
@@ -329,13 +329,14 @@ func fullConfigPath(fname string) (string, error) {
 	return path.Join(userConfigDir, "oddstream.games", "gosol", fname), nil
 }
 
-func makeConfigDir() {
+// makeConfigDir makes the directory fname is to be written to, which may be a subdirectory of the config directory
+func makeConfigDir(fname string) {
 	userConfigDir, err := os.UserConfigDir()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	dir := path.Join(userConfigDir, "oddstream.games", "gosol")
+	dir := path.Join(userConfigDir, "oddstream.games", "gosol", path.Dir(fname))
 	err = os.MkdirAll(dir, 0755) // https://stackoverflow.com/questions/14249467/os-mkdir-and-os-mkdirall-permission-value
 	if err != nil {
 		log.Fatal(err)
@@ -385,6 +386,32 @@ func LoadBytesFromFile(fname string, leaveNoTrace bool) ([]byte, int, error) {
 	return nil, 0, nil // file does not exist (which is ok)
 }
 
+// ConfigFileNames returns the names of the files in a directory in the config directory,
+// ready to be given to LoadBytesFromFile; a missing directory has no files
+func ConfigFileNames(dir string) []string {
+
+	if runtime.GOARCH == "wasm" {
+		log.Fatal("WASM detected")
+	}
+
+	path, err := fullConfigPath(dir)
+	if err != nil {
+		return nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil // directory does not exist (which is ok)
+	}
+	var fnames []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			fnames = append(fnames, dir+"/"+e.Name())
+		}
+	}
+	return fnames
+}
+
 func SaveBytesToFile(bytes []byte, fname string) {
 
 	if runtime.GOARCH == "wasm" {
@@ -396,7 +423,7 @@ func SaveBytesToFile(bytes []byte, fname string) {
 		log.Fatal(err)
 	}
 
-	makeConfigDir()
+	makeConfigDir(fname)
 
 	file, err := os.Create(path)
 	if err != nil {
@@ -414,3 +441,56 @@ func SaveBytesToFile(bytes []byte, fname string) {
 
 	log.Println("saved", path)
 }
+
+// AppendBytesToFile adds bytes to the end of a file in the config directory, making it if need be
+func AppendBytesToFile(bytes []byte, fname string) {
+
+	if runtime.GOARCH == "wasm" {
+		log.Fatal("WASM detected")
+	}
+
+	path, err := fullConfigPath(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	makeConfigDir(fname)
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if _, err = file.Write(bytes); err != nil {
+		log.Println(err)
+	}
+	if err = file.Close(); err != nil {
+		log.Println(err)
+	}
+}
+
+// ConfigDirNames returns the names of the directories in a directory in the config directory;
+// a missing directory has none
+func ConfigDirNames(dir string) []string {
+
+	if runtime.GOARCH == "wasm" {
+		log.Fatal("WASM detected")
+	}
+
+	path, err := fullConfigPath(dir)
+	if err != nil {
+		return nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil // directory does not exist (which is ok)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}